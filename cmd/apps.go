@@ -0,0 +1,500 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"path/filepath"
+)
+
+var (
+	appAllOrgsFlag                  bool
+	appShowSecretsFlag              bool
+	appRedirectUrisFlag             []string
+	appGrantTypesFlag               []string
+	appProviderCanSignUpFlag        bool
+	appProviderCanSignInFlag        bool
+	appProviderCanUnlinkFlag        bool
+	appProviderPromptedFlag         bool
+	appProviderRuleFlag             string
+	appSecretFileFlag               string
+	appSecretEnvFileFlag            string
+	appSecretEnvVarFlag             string
+	appAddDisplayFlag               string
+	appAddDescriptionFlag           string
+	appAddHomepageFlag              string
+	appAddCertFlag                  string
+	appAddTokenFormatFlag           string
+	appAddExpireHoursFlag           int
+	appAddRefreshExpireHoursFlag    int
+	appUpdateDisplayFlag            string
+	appUpdateDescriptionFlag        string
+	appUpdateHomepageFlag           string
+	appUpdateCertFlag               string
+	appUpdateTokenFormatFlag        string
+	appUpdateExpireHoursFlag        int
+	appUpdateRefreshExpireHoursFlag int
+)
+
+var appsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Manage Casdoor applications",
+	Long:  "Manage Casdoor applications, along with their redirect URIs, grant types, tokens and providers",
+}
+
+var appsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor applications",
+	Long:  "list the Casdoor applications of the organization. Client secrets are masked unless --show-secrets is given",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if err = checkShowSecrets(); err != nil {
+			return
+		}
+		if err = checkAllOrgs(appAllOrgsFlag); err != nil {
+			return
+		}
+		rotations, err := loadSecretRotations()
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		applications, err := userManager.GetApplications(appAllOrgsFlag, appShowSecretsFlag, rotations)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(applications)
+	},
+}
+
+var appsGetCmd = &cobra.Command{
+	Use:   "get <application>",
+	Short: "get a Casdoor application",
+	Long:  "get a Casdoor application. Its client secret is masked unless --show-secrets is given",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if err = checkShowSecrets(); err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		application, err := userManager.GetApplication(args[0], appShowSecretsFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(application)
+	},
+}
+
+var appsAddCmd = &cobra.Command{
+	Use:   "add <application>",
+	Short: "add a Casdoor application",
+	Long: `add a Casdoor application to the organization. Casdoor generates its client id and secret, which
+can be shown with apps get --show-secrets.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddApplication(args[0], helpers.ApplicationFields{
+			DisplayName:          appAddDisplayFlag,
+			Description:          appAddDescriptionFlag,
+			HomepageUrl:          appAddHomepageFlag,
+			Cert:                 appAddCertFlag,
+			RedirectUris:         appRedirectUrisFlag,
+			GrantTypes:           appGrantTypesFlag,
+			TokenFormat:          appAddTokenFormatFlag,
+			ExpireInHours:        appAddExpireHoursFlag,
+			RefreshExpireInHours: appAddRefreshExpireHoursFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsUpdateCmd = &cobra.Command{
+	Use:   "update <application>",
+	Short: "update a Casdoor application",
+	Long: `update a Casdoor application, such as its token format or the expiry of its tokens. Only the given
+fields are changed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.ApplicationUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &appUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("description") {
+			update.Description = &appUpdateDescriptionFlag
+		}
+		if cmd.Flags().Changed("homepage") {
+			update.HomepageUrl = &appUpdateHomepageFlag
+		}
+		if cmd.Flags().Changed("cert") {
+			update.Cert = &appUpdateCertFlag
+		}
+		if cmd.Flags().Changed("token-format") {
+			update.TokenFormat = &appUpdateTokenFormatFlag
+		}
+		if cmd.Flags().Changed("expire-hours") {
+			update.ExpireInHours = &appUpdateExpireHoursFlag
+		}
+		if cmd.Flags().Changed("refresh-expire-hours") {
+			update.RefreshExpireInHours = &appUpdateRefreshExpireHoursFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateApplication(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsDeleteCmd = &cobra.Command{
+	Use:   "delete <application>",
+	Short: "delete a Casdoor application",
+	Long:  "delete a Casdoor application. The application of the CLI cannot be deleted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the application %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteApplication(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsRotateSecretCmd = &cobra.Command{
+	Use:   "rotate-secret <application>",
+	Short: "rotate the client secret of a Casdoor application",
+	Long: `rotate the client secret of a Casdoor application. The new secret is shown once on the terminal,
+or written to a file (--file) or to a variable of an env file (--env-file). The previous secret stops
+working right away, so every consumer of the application must be given the new one. When the
+application is the one of the CLI, the CLI config is updated as well.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will replace the client secret of %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		rotations, err := loadSecretRotations()
+		if err != nil {
+			log.Fatal(err)
+		}
+		secret, err := helpers.GenerateClientSecret()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// files are staged before the application is updated, so that the new secret can't be lost,
+		// and only replace the previous secret once Casdoor has it
+		var staged *utils.StagedFile
+		switch {
+		case appSecretFileFlag != "":
+			staged, err = utils.StageSecretFile(appSecretFileFlag, secret)
+		case appSecretEnvFileFlag != "":
+			staged, err = utils.StageEnvFile(appSecretEnvFileFlag, appSecretEnvVarFlag, secret)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetApplicationSecret(args[0], secret)
+		if err != nil {
+			if staged != nil {
+				staged.Discard()
+			}
+			log.Fatal(err)
+		}
+
+		// Casdoor has the new secret from now on, so it is delivered before anything else can fail
+		if args[0] == config.ApplicationName {
+			if err = updateConfigValue("client_secret", secret); err != nil {
+				utils.Colorize(color.RedString, "[x] failed to update the client secret of the CLI config: %v", err)
+			} else {
+				utils.Colorize(color.GreenString, "[✔] client secret of the CLI config has been updated")
+			}
+		}
+		switch {
+		case staged != nil:
+			if err = staged.Commit(); err != nil {
+				utils.Colorize(color.RedString, "[x] failed to write the new client secret: %v", err)
+				utils.ShowOnce("Client secret", secret)
+			} else if appSecretFileFlag != "" {
+				utils.Colorize(color.GreenString, "[✔] new client secret written to %v", appSecretFileFlag)
+			} else {
+				utils.Colorize(color.GreenString, "[✔] new client secret written to %v in %v", appSecretEnvVarFlag, appSecretEnvFileFlag)
+			}
+		default:
+			utils.ShowOnce("Client secret", secret)
+		}
+
+		rotatedBy, err := currentUserId()
+		if err == nil {
+			err = rotations.Record(args[0], rotatedBy)
+		}
+		if err != nil {
+			utils.Colorize(color.YellowString, "[⚠] failed to record the rotation of the client secret: %v", err)
+		}
+	},
+}
+
+var appsRedirectUrisCmd = &cobra.Command{
+	Use:   "redirect-uris",
+	Short: "Manage the redirect URIs of a Casdoor application",
+	Long:  "Manage the redirect URIs of a Casdoor application, such as the redirect_uri of the CLI",
+}
+
+var appsRedirectUrisAddCmd = &cobra.Command{
+	Use:   "add <application> <uri>...",
+	Short: "add redirect URIs to an application",
+	Long:  "add redirect URIs to an application",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddRedirectUris(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsRedirectUrisRemoveCmd = &cobra.Command{
+	Use:   "remove <application> <uri>...",
+	Short: "remove redirect URIs from an application",
+	Long:  "remove redirect URIs from an application",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemoveRedirectUris(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsGrantTypesCmd = &cobra.Command{
+	Use:   "grant-types",
+	Short: "Manage the grant types of a Casdoor application",
+	Long: `Manage the grant types of a Casdoor application: authorization_code, password, client_credentials,
+token, id_token, refresh_token or urn:ietf:params:oauth:grant-type:device_code`,
+}
+
+var appsGrantTypesEnableCmd = &cobra.Command{
+	Use:   "enable <application> <grant-type>...",
+	Short: "enable grant types on an application",
+	Long:  "enable grant types on an application",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetGrantTypes(args[0], args[1:], true)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsGrantTypesDisableCmd = &cobra.Command{
+	Use:   "disable <application> <grant-type>...",
+	Short: "disable grant types on an application",
+	Long:  "disable grant types on an application",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetGrantTypes(args[0], args[1:], false)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsProvidersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Manage the providers of a Casdoor application",
+	Long:  "Manage the providers of a Casdoor application, such as its captcha, email or OAuth providers",
+}
+
+var appsProvidersListCmd = &cobra.Command{
+	Use:   "list <application>",
+	Short: "list the providers of an application",
+	Long:  "list the providers of an application",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		providers, err := userManager.GetApplicationProviders(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(providers)
+	},
+}
+
+var appsProvidersAddCmd = &cobra.Command{
+	Use:   "add <application> <provider>",
+	Short: "add a provider to an application",
+	Long: `add a provider to an application, or change its settings if the application already has it, in
+which case only the given settings are changed`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var fields helpers.ApplicationProviderFields
+		if cmd.Flags().Changed("can-sign-up") {
+			fields.CanSignUp = &appProviderCanSignUpFlag
+		}
+		if cmd.Flags().Changed("can-sign-in") {
+			fields.CanSignIn = &appProviderCanSignInFlag
+		}
+		if cmd.Flags().Changed("can-unlink") {
+			fields.CanUnlink = &appProviderCanUnlinkFlag
+		}
+		if cmd.Flags().Changed("prompted") {
+			fields.Prompted = &appProviderPromptedFlag
+		}
+		if cmd.Flags().Changed("rule") {
+			fields.Rule = &appProviderRuleFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddApplicationProvider(args[0], args[1], fields)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var appsProvidersRemoveCmd = &cobra.Command{
+	Use:   "remove <application> <provider>",
+	Short: "remove a provider from an application",
+	Long:  "remove a provider from an application. The provider itself is kept",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemoveApplicationProvider(args[0], args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// checkShowSecrets authorizes --show-secrets on its own, so that the users allowed to list
+// applications don't see their client secrets unless the policy allows them to
+func checkShowSecrets() error {
+	if !appShowSecretsFlag {
+		return nil
+	}
+	_, err := checkLoggedInAndGetConfigForPath("apps.secrets")
+	return err
+}
+
+func loadSecretRotations() (*helpers.SecretRotations, error) {
+	casdoorFolder, _, err := getCasdoorFolderAndConfig()
+	if err != nil {
+		return nil, err
+	}
+	return helpers.LoadSecretRotations(filepath.Join(casdoorFolder, "secret-rotations.json"))
+}
+
+func init() {
+	RootCmd.AddCommand(appsCmd)
+	appsCmd.AddCommand(appsListCmd)
+	appsCmd.AddCommand(appsGetCmd)
+	appsCmd.AddCommand(appsAddCmd)
+	appsCmd.AddCommand(appsUpdateCmd)
+	appsCmd.AddCommand(appsDeleteCmd)
+	appsCmd.AddCommand(appsRotateSecretCmd)
+	appsCmd.AddCommand(appsRedirectUrisCmd)
+	appsRedirectUrisCmd.AddCommand(appsRedirectUrisAddCmd)
+	appsRedirectUrisCmd.AddCommand(appsRedirectUrisRemoveCmd)
+	appsCmd.AddCommand(appsGrantTypesCmd)
+	appsGrantTypesCmd.AddCommand(appsGrantTypesEnableCmd)
+	appsGrantTypesCmd.AddCommand(appsGrantTypesDisableCmd)
+	appsCmd.AddCommand(appsProvidersCmd)
+	appsProvidersCmd.AddCommand(appsProvidersListCmd)
+	appsProvidersCmd.AddCommand(appsProvidersAddCmd)
+	appsProvidersCmd.AddCommand(appsProvidersRemoveCmd)
+	appsListCmd.Flags().BoolVar(&appAllOrgsFlag, "all-orgs", false, "list the applications of every organization")
+	appsListCmd.Flags().BoolVar(&appShowSecretsFlag, "show-secrets", false, "show the client secrets")
+	appsGetCmd.Flags().BoolVar(&appShowSecretsFlag, "show-secrets", false, "show the client secret")
+	appsUpdateCmd.Flags().StringVar(&appUpdateDisplayFlag, "display-name", "", "display name of the application")
+	appsUpdateCmd.Flags().StringVar(&appUpdateDescriptionFlag, "description", "", "description of the application")
+	appsUpdateCmd.Flags().StringVar(&appUpdateHomepageFlag, "homepage", "", "homepage URL of the application")
+	appsUpdateCmd.Flags().StringVar(&appUpdateCertFlag, "cert", "", "certificate signing the tokens of the application")
+	appsUpdateCmd.Flags().StringVar(&appUpdateTokenFormatFlag, "token-format", "", "format of the tokens (JWT, JWT-Empty, JWT-Custom or JWT-Standard)")
+	appsUpdateCmd.Flags().IntVar(&appUpdateExpireHoursFlag, "expire-hours", 0, "lifetime of the access tokens in hours")
+	appsUpdateCmd.Flags().IntVar(&appUpdateRefreshExpireHoursFlag, "refresh-expire-hours", 0, "lifetime of the refresh tokens in hours")
+	appsAddCmd.Flags().StringVar(&appAddDisplayFlag, "display-name", "", "display name of the application")
+	appsAddCmd.Flags().StringVar(&appAddDescriptionFlag, "description", "", "description of the application")
+	appsAddCmd.Flags().StringVar(&appAddHomepageFlag, "homepage", "", "homepage URL of the application")
+	appsAddCmd.Flags().StringVar(&appAddCertFlag, "cert", "cert-built-in", "certificate signing the tokens of the application")
+	appsAddCmd.Flags().StringArrayVar(&appRedirectUrisFlag, "redirect-uri", nil, "redirect URI of the application (repeatable)")
+	appsAddCmd.Flags().StringArrayVar(&appGrantTypesFlag, "grant-type", []string{"authorization_code"}, "grant type of the application (repeatable)")
+	appsAddCmd.Flags().StringVar(&appAddTokenFormatFlag, "token-format", "JWT", "format of the tokens (JWT, JWT-Empty, JWT-Custom or JWT-Standard)")
+	appsAddCmd.Flags().IntVar(&appAddExpireHoursFlag, "expire-hours", 168, "lifetime of the access tokens in hours")
+	appsAddCmd.Flags().IntVar(&appAddRefreshExpireHoursFlag, "refresh-expire-hours", 168, "lifetime of the refresh tokens in hours")
+	appsRotateSecretCmd.Flags().StringVar(&appSecretFileFlag, "file", "", "file to write the new client secret to")
+	appsRotateSecretCmd.Flags().StringVar(&appSecretEnvFileFlag, "env-file", "", "env file to write the new client secret to")
+	appsRotateSecretCmd.Flags().StringVar(&appSecretEnvVarFlag, "env-var", "CASDOOR_CLIENT_SECRET", "variable of the env file holding the client secret")
+	appsRotateSecretCmd.MarkFlagsMutuallyExclusive("file", "env-file")
+	appsProvidersAddCmd.Flags().BoolVar(&appProviderCanSignUpFlag, "can-sign-up", false, "whether users can sign up with the provider")
+	appsProvidersAddCmd.Flags().BoolVar(&appProviderCanSignInFlag, "can-sign-in", false, "whether users can sign in with the provider")
+	appsProvidersAddCmd.Flags().BoolVar(&appProviderCanUnlinkFlag, "can-unlink", false, "whether users can unlink the provider from their account")
+	appsProvidersAddCmd.Flags().BoolVar(&appProviderPromptedFlag, "prompted", false, "whether users are prompted to link the provider")
+	appsProvidersAddCmd.Flags().StringVar(&appProviderRuleFlag, "rule", "", "rule of the provider, such as None or Default for captcha providers")
+}
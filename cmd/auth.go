@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/models"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+)
+
+var (
+	canIListFlag bool
+	canIAsFlag   string
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect your Casdoor CLI authorizations",
+	Long:  "Inspect your Casdoor CLI authorizations",
+}
+
+var authCanICmd = &cobra.Command{
+	Use:   "can-i <verb> <resource>",
+	Short: "check whether you may run a command",
+	Long: `check whether you may run a command, such as "can-i delete users" for users delete or
+"can-i add users.groups" for users groups add. The same authorization logic as the commands themselves
+is used, and the rule which granted or denied access is shown. With --list, every command is checked.
+
+Administrators may check the rights of another user with --as <user>.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if canIListFlag {
+			return cobra.NoArgs(cmd, args)
+		}
+		if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+			return err
+		}
+		if !containsCommand(authorizedCommandPaths(RootCmd), args[1]+"."+args[0]) {
+			return fmt.Errorf("unknown command %v %v, see casdoor auth can-i --list for the commands", args[0], args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var config *models.CasdoorConfig
+		var tokenData *models.TokenData
+		var err error
+
+		if canIAsFlag != "" {
+			// checking the rights of someone else is itself subject to the policy
+			config, err = checkLoggedInAndGetConfigForPath("auth.can-i.as")
+			if err != nil {
+				return
+			}
+			tokenData, err = tokenDataOf(config, canIAsFlag)
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			config, err = initCasdoorConfig()
+			if err != nil {
+				log.Fatal(err)
+			}
+			tokenData, err = utils.KeyringToTokenData()
+			if err != nil {
+				utils.Colorize(color.RedString, "[x] you are not logged in. You can log in using casdoor login")
+				return
+			}
+		}
+
+		var paths []string
+		if canIListFlag {
+			paths = authorizedCommandPaths(RootCmd)
+		} else {
+			paths = []string{args[1] + "." + args[0]}
+		}
+
+		var decisions []authDecision
+		if len(paths) == 1 {
+			decision, err := authorize(config, tokenData, paths[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+			decisions = []authDecision{decision}
+		} else {
+			decisions, err = authorizeAll(config, tokenData, paths)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		var decisionList []map[string]interface{}
+		for i, decision := range decisions {
+			decisionList = append(decisionList, map[string]interface{}{
+				"Command": paths[i],
+				"Allowed": decision.Allowed,
+				"Source":  decision.Source,
+				"Rule":    decision.Rule,
+			})
+		}
+		utils.PrintTables(decisionList)
+
+		if !canIListFlag && !decisions[0].Allowed {
+			os.Exit(1)
+		}
+	},
+}
+
+// tokenDataOf builds the token data another user would have, from its Casdoor profile.
+func tokenDataOf(config *models.CasdoorConfig, name string) (*models.TokenData, error) {
+	userManager := helpers.NewUserManager(config)
+	groups, err := userManager.GetUserGroups(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenData := new(models.TokenData)
+	tokenData.IDTokenClaims.Owner = config.OrganizationName
+	tokenData.IDTokenClaims.Name = name
+	tokenData.IDTokenClaims.Groups = groups
+	tokenData.FromProfile = true
+	if len(groups) == 0 {
+		tokenData.IDTokenClaims.Groups = []string{"not settled"}
+	}
+	log.Debugf("checking the rights of %v with groups %v", name, strings.Join(groups, ", "))
+	return tokenData, nil
+}
+
+func init() {
+	RootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authCanICmd)
+	authCanICmd.Flags().BoolVarP(&canIListFlag, "list", "l", false, "check every command")
+	authCanICmd.Flags().StringVar(&canIAsFlag, "as", "", "check the rights of another user (administrators only)")
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/models"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	authorizationModeLocal   = "local"
+	authorizationModeCasdoor = "casdoor"
+	authorizationModeBoth    = "both"
+
+	// otherOrgsPath is the command path --org and --all-orgs are authorized as, on top of the
+	// command itself, when they reach beyond the organization of the config
+	otherOrgsPath = "orgs.switch"
+)
+
+// authDecision is the outcome of an authorization check, along with the rule which led to it.
+type authDecision struct {
+	Allowed bool
+	Source  string
+	Rule    string
+}
+
+// commandPath returns the dot separated path of a command, such as "users.groups.add".
+func commandPath(cmd *cobra.Command) string {
+	return strings.Join(strings.Fields(cmd.CommandPath())[1:], ".")
+}
+
+// commandAction returns the Casbin object and action of a command path: "users.add" is the
+// action "add" on "users", and "users.groups.add" the action "add" on "users.groups".
+func commandAction(path string) (string, string) {
+	i := strings.LastIndex(path, ".")
+	if i < 0 {
+		return path, ""
+	}
+	return path[:i], path[i+1:]
+}
+
+// authorize checks whether the logged-in user may run the command, according to the authorization
+// mode of the config. In "local" mode, the groups of the user are matched against the policy rule of
+// the command. In "casdoor" mode, Casdoor is asked through Enforce with the configured permission or
+// model. In "both" mode, both checks must allow the command.
+func authorize(config *models.CasdoorConfig, tokenData *models.TokenData, path string) (authDecision, error) {
+	mode := config.AuthorizationMode
+	if mode == "" {
+		mode = authorizationModeLocal
+	}
+	object, action := commandAction(path)
+
+	switch mode {
+	case authorizationModeLocal:
+		return authorizeLocally(config, tokenData, path)
+	case authorizationModeCasdoor:
+		return authorizeWithCasdoor(config, tokenData, object, action)
+	case authorizationModeBoth:
+		decision, err := authorizeLocally(config, tokenData, path)
+		if err != nil || !decision.Allowed {
+			return decision, err
+		}
+		return authorizeWithCasdoor(config, tokenData, object, action)
+	default:
+		return authDecision{}, fmt.Errorf("unknown authorization mode %v (expected %v, %v or %v)", mode, authorizationModeLocal, authorizationModeCasdoor, authorizationModeBoth)
+	}
+}
+
+// authorizeLocally matches the groups of the user against the first policy rule matching the command.
+func authorizeLocally(config *models.CasdoorConfig, tokenData *models.TokenData, path string) (authDecision, error) {
+	policy, err := loadPolicy(config)
+	if err != nil {
+		return authDecision{}, err
+	}
+
+	groups := tokenData.IDTokenClaims.Groups
+	rule, ok := policy.Match(path)
+	if !ok {
+		return authDecision{
+			Allowed: false,
+			Source:  authorizationModeLocal,
+			Rule:    fmt.Sprintf("no rule of %v matches %v", policy.Source, path),
+		}, nil
+	}
+
+	decision := authDecision{
+		Allowed: rule.Allows(groups),
+		Source:  authorizationModeLocal,
+	}
+	if decision.Allowed {
+		decision.Rule = fmt.Sprintf("rule %v of %v allows [%v], groups [%v] match", rule.Command, policy.Source, strings.Join(rule.Allow, ", "), strings.Join(groups, ", "))
+	} else {
+		decision.Rule = fmt.Sprintf("rule %v of %v allows [%v], groups [%v] don't match", rule.Command, policy.Source, strings.Join(rule.Allow, ", "), strings.Join(groups, ", "))
+	}
+	return decision, nil
+}
+
+// loadPolicy loads the policy file of the config, ~/.casdoor-cli/policy.yaml unless policy_file is set.
+func loadPolicy(config *models.CasdoorConfig) (*helpers.Policy, error) {
+	path := config.PolicyFile
+	if path == "" {
+		casdoorFolder, _, err := getCasdoorFolderAndConfig()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(casdoorFolder, "policy.yaml")
+	}
+	return helpers.LoadPolicy(path)
+}
+
+// authorizeWithCasdoor asks Casdoor for a decision.
+func authorizeWithCasdoor(config *models.CasdoorConfig, tokenData *models.TokenData, object string, action string) (authDecision, error) {
+	if config.PermissionID == "" && config.ModelID == "" {
+		return authDecision{}, fmt.Errorf("authorization mode %v requires authorization_permission_id or authorization_model_id in the config", config.AuthorizationMode)
+	}
+
+	subject, err := casdoorSubject(config, tokenData)
+	if err != nil {
+		return authDecision{}, err
+	}
+	rule := fmt.Sprintf("enforce(%s, %s, %s) with permission %q model %q", subject, object, action, config.PermissionID, config.ModelID)
+
+	userManager := helpers.NewUserManager(config)
+	allowed, err := userManager.Enforce(config.PermissionID, config.ModelID, subject, object, action)
+	if err != nil {
+		return authDecision{}, err
+	}
+	return authDecision{Allowed: allowed, Source: authorizationModeCasdoor, Rule: rule}, nil
+}
+
+// casdoorSubject returns the <organization>/<user> subject Casdoor is asked about. The claims
+// stored in the keyring could be edited to impersonate anyone, so the subject is taken from the
+// access token once its signature is checked against the certificate of the config.
+func casdoorSubject(config *models.CasdoorConfig, tokenData *models.TokenData) (string, error) {
+	if tokenData.FromProfile {
+		return fmt.Sprintf("%s/%s", tokenData.IDTokenClaims.Owner, tokenData.IDTokenClaims.Name), nil
+	}
+	claims, err := helpers.NewUserManager(config).VerifyAccessToken(tokenData.OAuth2Token.AccessToken)
+	if err != nil {
+		return "", fmt.Errorf("invalid session (%v), please log in again using casdoor login", err)
+	}
+	return fmt.Sprintf("%s/%s", claims.Owner, claims.Name), nil
+}
+
+// authorizeAll evaluates every command path at once. Casdoor is asked through a single BatchEnforce
+// call instead of one Enforce call per command.
+func authorizeAll(config *models.CasdoorConfig, tokenData *models.TokenData, paths []string) ([]authDecision, error) {
+	mode := config.AuthorizationMode
+	if mode == "" {
+		mode = authorizationModeLocal
+	}
+	if mode != authorizationModeLocal && mode != authorizationModeCasdoor && mode != authorizationModeBoth {
+		return nil, fmt.Errorf("unknown authorization mode %v (expected %v, %v or %v)", mode, authorizationModeLocal, authorizationModeCasdoor, authorizationModeBoth)
+	}
+
+	decisions := make([]authDecision, len(paths))
+	if mode == authorizationModeLocal || mode == authorizationModeBoth {
+		for i, path := range paths {
+			decision, err := authorizeLocally(config, tokenData, path)
+			if err != nil {
+				return nil, err
+			}
+			decisions[i] = decision
+		}
+		if mode == authorizationModeLocal {
+			return decisions, nil
+		}
+	}
+
+	if config.PermissionID == "" && config.ModelID == "" {
+		return nil, fmt.Errorf("authorization mode %v requires authorization_permission_id or authorization_model_id in the config", mode)
+	}
+	subject, err := casdoorSubject(config, tokenData)
+	if err != nil {
+		return nil, err
+	}
+	var requests [][]string
+	for _, path := range paths {
+		object, action := commandAction(path)
+		requests = append(requests, []string{subject, object, action})
+	}
+
+	userManager := helpers.NewUserManager(config)
+	allowed, err := userManager.BatchEnforce(config.PermissionID, config.ModelID, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, request := range requests {
+		if mode == authorizationModeBoth && !decisions[i].Allowed {
+			continue
+		}
+		decisions[i] = authDecision{
+			Allowed: allowed[i],
+			Source:  authorizationModeCasdoor,
+			Rule:    fmt.Sprintf("enforce(%s, %s, %s) with permission %q model %q", request[0], request[1], request[2], config.PermissionID, config.ModelID),
+		}
+	}
+	return decisions, nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/golang-jwt/jwt/v4"
+	"gitlab.com/sdv9972401/casdoor-cli/models"
+)
+
+func TestCommandAction(t *testing.T) {
+	tests := []struct {
+		path   string
+		object string
+		action string
+	}{
+		{"users.add", "users", "add"},
+		{"users.groups.add", "users.groups", "add"},
+		{"whoami", "whoami", ""},
+	}
+
+	for _, tt := range tests {
+		object, action := commandAction(tt.path)
+		if object != tt.object || action != tt.action {
+			t.Errorf("commandAction(%q) = %q, %q, want %q, %q", tt.path, object, action, tt.object, tt.action)
+		}
+	}
+}
+
+func TestAuthorizeLocally(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	content := `rules:
+  - command: users.set-password
+    allow: [helpdesk]
+  - command: "**"
+    allow: [administrator]
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mode    string
+		path    string
+		groups  []string
+		allowed bool
+		wantErr bool
+	}{
+		{"", "users.set-password", []string{"helpdesk"}, true, false},
+		{"local", "users.set-password", []string{"helpdesk"}, true, false},
+		{"local", "users.delete", []string{"helpdesk"}, false, false},
+		{"local", "users.delete", []string{"administrator"}, true, false},
+		{"local", "users.delete", nil, false, false},
+		{"remote", "users.delete", []string{"administrator"}, false, true},
+		{"casdoor", "users.delete", []string{"administrator"}, false, true},
+	}
+
+	for _, tt := range tests {
+		config := &models.CasdoorConfig{AuthorizationMode: tt.mode, PolicyFile: path}
+		tokenData := &models.TokenData{}
+		tokenData.IDTokenClaims.Groups = tt.groups
+
+		decision, err := authorize(config, tokenData, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("authorize(%q, %q, %v) error = %v, wantErr %v", tt.mode, tt.path, tt.groups, err, tt.wantErr)
+			continue
+		}
+		if decision.Allowed != tt.allowed {
+			t.Errorf("authorize(%q, %q, %v) = %+v, want allowed %v", tt.mode, tt.path, tt.groups, decision, tt.allowed)
+		}
+	}
+}
+
+func TestCasdoorSubject(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "casdoor-cli test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &models.CasdoorConfig{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+
+	claims := casdoorsdk.Claims{
+		User: casdoorsdk.User{Owner: "org", Name: "alice"},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the payload of a tampered token claims to be bob while keeping the signature of alice
+	parts := strings.Split(token, ".")
+	claims.User.Name = "bob"
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Join([]string{parts[0], strings.Split(forged, ".")[1], parts[2]}, ".")
+
+	tests := []struct {
+		name        string
+		accessToken string
+		keyringName string
+		fromProfile bool
+		want        string
+		wantErr     bool
+	}{
+		{"verified token", token, "mallory", false, "org/alice", false},
+		{"tampered token", tampered, "bob", false, "", true},
+		{"no token", "", "alice", false, "", true},
+		{"profile", "", "alice", true, "org/alice", false},
+	}
+
+	for _, tt := range tests {
+		tokenData := &models.TokenData{FromProfile: tt.fromProfile}
+		tokenData.OAuth2Token.AccessToken = tt.accessToken
+		tokenData.IDTokenClaims.Owner = "org"
+		tokenData.IDTokenClaims.Name = tt.keyringName
+
+		subject, err := casdoorSubject(config, tokenData)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%v: casdoorSubject() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if subject != tt.want {
+			t.Errorf("%v: casdoorSubject() = %q, want %q", tt.name, subject, tt.want)
+		}
+	}
+}
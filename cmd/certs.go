@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+)
+
+var (
+	certGlobalFlag            bool
+	certAddAlgorithmFlag      string
+	certGenerateAlgorithmFlag string
+	certBitSizeFlag           int
+	certExpireYearsFlag       int
+	certReplaceFlag           bool
+	certForceFlag             bool
+	certJwksFlag              bool
+	certPemFlag               bool
+	certFileFlag              string
+	certDaysFlag              int
+	certRefreshConfigFlag     bool
+	certAddDisplayFlag        string
+	certAddScopeFlag          string
+	certAddCertificateFlag    string
+	certAddPrivateKeyFlag     string
+	certUpdateDisplayFlag     string
+	certUpdateScopeFlag       string
+	certUpdateCertificateFlag string
+	certUpdatePrivateKeyFlag  string
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage Casdoor certificates",
+	Long:  "Manage Casdoor certificates, which sign the tokens issued by applications",
+}
+
+var certsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor certificates",
+	Long:  "list the Casdoor certificates of the organization, or every certificate with --global",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		certs, err := userManager.GetCerts(certGlobalFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(certs)
+	},
+}
+
+var certsGetCmd = &cobra.Command{
+	Use:   "get <cert>",
+	Short: "get a Casdoor certificate",
+	Long:  "get a Casdoor certificate. Its private key is never shown",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		cert, err := userManager.GetCert(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(cert)
+	},
+}
+
+var certsAddCmd = &cobra.Command{
+	Use:   "add <cert>",
+	Short: "add a Casdoor certificate",
+	Long:  "add a Casdoor certificate from PEM files holding the certificate and its private key",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		certificate, err := os.ReadFile(certAddCertificateFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		privateKey, err := os.ReadFile(certAddPrivateKeyFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddCert(args[0], helpers.CertFields{
+			DisplayName:     certAddDisplayFlag,
+			Scope:           certAddScopeFlag,
+			CryptoAlgorithm: certAddAlgorithmFlag,
+			Certificate:     string(certificate),
+			PrivateKey:      string(privateKey),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var certsUpdateCmd = &cobra.Command{
+	Use:   "update <cert>",
+	Short: "update a Casdoor certificate",
+	Long: `update a Casdoor certificate. Only the given fields are changed, and a new certificate or
+private key must match the other one.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.CertUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &certUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("scope") {
+			update.Scope = &certUpdateScopeFlag
+		}
+		if cmd.Flags().Changed("certificate") {
+			certificate, err := os.ReadFile(certUpdateCertificateFlag)
+			if err != nil {
+				log.Fatal(err)
+			}
+			content := string(certificate)
+			update.Certificate = &content
+		}
+		if cmd.Flags().Changed("private-key") {
+			privateKey, err := os.ReadFile(certUpdatePrivateKeyFlag)
+			if err != nil {
+				log.Fatal(err)
+			}
+			content := string(privateKey)
+			update.PrivateKey = &content
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateCert(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var certsDeleteCmd = &cobra.Command{
+	Use:   "delete <cert>",
+	Short: "delete a Casdoor certificate",
+	Long:  "delete a Casdoor certificate. A certificate still used by applications is only deleted with --force",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the cert %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteCert(args[0], certForceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var certsGenerateCmd = &cobra.Command{
+	Use:   "generate <cert>",
+	Short: "generate a Casdoor certificate",
+	Long: `generate an RSA or ECDSA key pair and a self-signed certificate locally, and upload them to
+Casdoor. With --replace, the key pair of an existing certificate is rotated, which invalidates the
+tokens it signed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if certReplaceFlag && !userConfirms("[⚠] This will replace the key pair of the cert %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.GenerateCert(args[0], certGenerateAlgorithmFlag, certBitSizeFlag, certExpireYearsFlag, certReplaceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var certsExportCmd = &cobra.Command{
+	Use:   "export <cert>",
+	Short: "export the public part of a Casdoor certificate",
+	Long: `export the public part of a Casdoor certificate, either as PEM (--pem, the default) or as a JSON
+Web Key Set (--jwks), to a file or to the standard output without --file.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		exported, err := userManager.ExportCert(args[0], certJwksFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if certFileFlag == "" {
+			fmt.Print(exported)
+			return
+		}
+		err = os.WriteFile(certFileFlag, []byte(exported), 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.Colorize(color.GreenString, "[✔] %v exported to %v", args[0], certFileFlag)
+	},
+}
+
+var certsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "check the expiry of Casdoor certificates",
+	Long: `check the expiry of Casdoor certificates, and exit with status 1 when one expires within --days.
+With --refresh-config, the certificate of the CLI config is replaced by the one of the CLI application
+when they differ.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+
+		if certRefreshConfigFlag {
+			certificate, err := userManager.GetApplicationCertificate(config.ApplicationName)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if strings.TrimSpace(certificate) == strings.TrimSpace(config.Certificate) {
+				utils.Colorize(color.CyanString, "[ℹ] the certificate of the CLI config is up to date")
+			} else {
+				if err = updateConfigValue("certificate", certificate); err != nil {
+					log.Fatal(err)
+				}
+				utils.Colorize(color.GreenString, "[✔] the certificate of the CLI config has been refreshed")
+			}
+		}
+
+		certs, expiring, err := userManager.CheckCerts(certGlobalFlag, certDaysFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(certs)
+		if expiring > 0 {
+			utils.Colorize(color.YellowString, "[⚠] %d certificates expire within %d days", expiring, certDaysFlag)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(certsCmd)
+	certsCmd.AddCommand(certsListCmd)
+	certsCmd.AddCommand(certsGetCmd)
+	certsCmd.AddCommand(certsAddCmd)
+	certsCmd.AddCommand(certsUpdateCmd)
+	certsCmd.AddCommand(certsDeleteCmd)
+	certsCmd.AddCommand(certsGenerateCmd)
+	certsCmd.AddCommand(certsExportCmd)
+	certsCmd.AddCommand(certsCheckCmd)
+	certsListCmd.Flags().BoolVar(&certGlobalFlag, "global", false, "list the certificates of every organization")
+	certsUpdateCmd.Flags().StringVar(&certUpdateDisplayFlag, "display-name", "", "display name of the certificate")
+	certsUpdateCmd.Flags().StringVar(&certUpdateScopeFlag, "scope", "", "scope of the certificate")
+	certsUpdateCmd.Flags().StringVar(&certUpdateCertificateFlag, "certificate", "", "PEM file holding the certificate")
+	certsUpdateCmd.Flags().StringVar(&certUpdatePrivateKeyFlag, "private-key", "", "PEM file holding the private key")
+	certsAddCmd.Flags().StringVar(&certAddDisplayFlag, "display-name", "", "display name of the certificate")
+	certsAddCmd.Flags().StringVar(&certAddScopeFlag, "scope", "JWT", "scope of the certificate")
+	certsAddCmd.Flags().StringVar(&certAddAlgorithmFlag, "algorithm", "", "signing algorithm, guessed from the key when empty (RS256, RS384, RS512, ES256, ES384 or ES512)")
+	certsAddCmd.Flags().StringVar(&certAddCertificateFlag, "certificate", "", "PEM file holding the certificate")
+	certsAddCmd.MarkFlagRequired("certificate")
+	certsAddCmd.Flags().StringVar(&certAddPrivateKeyFlag, "private-key", "", "PEM file holding the private key")
+	certsAddCmd.MarkFlagRequired("private-key")
+	certsDeleteCmd.Flags().BoolVar(&certForceFlag, "force", false, "delete the certificate even if applications use it")
+	certsGenerateCmd.Flags().StringVar(&certGenerateAlgorithmFlag, "algorithm", "RS256", "signing algorithm (RS256, RS384, RS512, ES256, ES384 or ES512)")
+	certsGenerateCmd.Flags().IntVar(&certBitSizeFlag, "bit-size", 4096, "size of RSA keys in bits")
+	certsGenerateCmd.Flags().IntVar(&certExpireYearsFlag, "expire-years", 20, "validity of the certificate in years")
+	certsGenerateCmd.Flags().BoolVar(&certReplaceFlag, "replace", false, "rotate the key pair of an existing certificate")
+	certsExportCmd.Flags().BoolVar(&certJwksFlag, "jwks", false, "export as a JSON Web Key Set")
+	certsExportCmd.Flags().BoolVar(&certPemFlag, "pem", false, "export as PEM (default)")
+	certsExportCmd.MarkFlagsMutuallyExclusive("jwks", "pem")
+	certsExportCmd.Flags().StringVarP(&certFileFlag, "file", "f", "", "file to export the certificate to")
+	certsCheckCmd.Flags().BoolVar(&certGlobalFlag, "global", false, "check the certificates of every organization")
+	certsCheckCmd.Flags().IntVar(&certDaysFlag, "days", 30, "warn about certificates expiring within this number of days")
+	certsCheckCmd.Flags().BoolVar(&certRefreshConfigFlag, "refresh-config", false, "refresh the certificate of the CLI config from the CLI application")
+}
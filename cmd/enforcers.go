@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	enforcerDisplayFlag     string
+	enforcerDescriptionFlag string
+	enforcerModelFlag       string
+	enforcerAdapterFlag     string
+	adapterTypeFlag         string
+	adapterDatabaseTypeFlag string
+	adapterHostFlag         string
+	adapterPortFlag         int
+	adapterUserFlag         string
+	adapterDatabaseFlag     string
+	adapterTableFlag        string
+	adapterForceFlag        bool
+)
+
+var enforcersCmd = &cobra.Command{
+	Use:   "enforcers",
+	Short: "Manage Casdoor enforcers",
+	Long:  "Manage Casdoor enforcers, which evaluate the policies stored by an adapter with a Casbin model",
+}
+
+var enforcersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor enforcers",
+	Long:  "list Casdoor enforcers",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		enforcers, err := userManager.GetEnforcers()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(enforcers)
+	},
+}
+
+var enforcersGetCmd = &cobra.Command{
+	Use:   "get <enforcer>",
+	Short: "get a Casdoor enforcer",
+	Long:  "get a Casdoor enforcer",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		enforcer, err := userManager.GetEnforcer(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(enforcer)
+	},
+}
+
+var enforcersAddCmd = &cobra.Command{
+	Use:   "add <enforcer>",
+	Short: "add a Casdoor enforcer",
+	Long:  "add a Casdoor enforcer evaluating the policies of an adapter (--adapter) with a Casbin model (--model)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddEnforcer(args[0], helpers.EnforcerFields{
+			DisplayName: enforcerDisplayFlag,
+			Description: enforcerDescriptionFlag,
+			Model:       enforcerModelFlag,
+			Adapter:     enforcerAdapterFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var enforcersDeleteCmd = &cobra.Command{
+	Use:   "delete <enforcer>",
+	Short: "delete a Casdoor enforcer",
+	Long:  "delete a Casdoor enforcer. Its policies are kept in its adapter",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the enforcer %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteEnforcer(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var adaptersCmd = &cobra.Command{
+	Use:   "adapters",
+	Short: "Manage Casdoor adapters",
+	Long:  "Manage Casdoor adapters, which store the policies of enforcers",
+}
+
+var adaptersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor adapters",
+	Long:  "list Casdoor adapters",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		adapters, err := userManager.GetAdapters()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(adapters)
+	},
+}
+
+var adaptersGetCmd = &cobra.Command{
+	Use:   "get <adapter>",
+	Short: "get a Casdoor adapter",
+	Long:  "get a Casdoor adapter. Its password is never shown",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		adapter, err := userManager.GetAdapter(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(adapter)
+	},
+}
+
+var adaptersAddCmd = &cobra.Command{
+	Use:   "add <adapter>",
+	Short: "add a Casdoor adapter",
+	Long: `add a Casdoor adapter. A Database adapter stores its policies in the given database and table,
+and its password is prompted for when --user is set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		password := ""
+		if adapterUserFlag != "" {
+			passwordPrompt := promptui.Prompt{
+				Label: "Database password",
+				Mask:  '*',
+			}
+			password, err = passwordPrompt.Run()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddAdapter(args[0], helpers.AdapterFields{
+			Type:         adapterTypeFlag,
+			DatabaseType: adapterDatabaseTypeFlag,
+			Host:         adapterHostFlag,
+			Port:         adapterPortFlag,
+			User:         adapterUserFlag,
+			Password:     password,
+			Database:     adapterDatabaseFlag,
+			Table:        adapterTableFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var adaptersDeleteCmd = &cobra.Command{
+	Use:   "delete <adapter>",
+	Short: "delete a Casdoor adapter",
+	Long:  "delete a Casdoor adapter. An adapter still used by enforcers is only deleted with --force",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the adapter %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteAdapter(args[0], adapterForceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(enforcersCmd)
+	enforcersCmd.AddCommand(enforcersListCmd)
+	enforcersCmd.AddCommand(enforcersGetCmd)
+	enforcersCmd.AddCommand(enforcersAddCmd)
+	enforcersCmd.AddCommand(enforcersDeleteCmd)
+	enforcersAddCmd.Flags().StringVar(&enforcerDisplayFlag, "display-name", "", "display name of the enforcer")
+	enforcersAddCmd.Flags().StringVar(&enforcerDescriptionFlag, "description", "", "description of the enforcer")
+	enforcersAddCmd.Flags().StringVar(&enforcerModelFlag, "model", "", "Casbin model of the enforcer")
+	enforcersAddCmd.MarkFlagRequired("model")
+	enforcersAddCmd.Flags().StringVar(&enforcerAdapterFlag, "adapter", "", "adapter storing the policies of the enforcer")
+	enforcersAddCmd.MarkFlagRequired("adapter")
+
+	RootCmd.AddCommand(adaptersCmd)
+	adaptersCmd.AddCommand(adaptersListCmd)
+	adaptersCmd.AddCommand(adaptersGetCmd)
+	adaptersCmd.AddCommand(adaptersAddCmd)
+	adaptersCmd.AddCommand(adaptersDeleteCmd)
+	adaptersAddCmd.Flags().StringVar(&adapterTypeFlag, "type", "Database", "type of the adapter")
+	adaptersAddCmd.Flags().StringVar(&adapterDatabaseTypeFlag, "database-type", "mysql", "type of the database (mysql, postgres, mssql, sqlite3...)")
+	adaptersAddCmd.Flags().StringVar(&adapterHostFlag, "host", "", "host of the database")
+	adaptersAddCmd.Flags().IntVar(&adapterPortFlag, "port", 0, "port of the database")
+	adaptersAddCmd.Flags().StringVar(&adapterUserFlag, "user", "", "user of the database")
+	adaptersAddCmd.Flags().StringVar(&adapterDatabaseFlag, "database", "", "name of the database")
+	adaptersAddCmd.Flags().StringVar(&adapterTableFlag, "table", "casbin_rule", "table storing the policies")
+	adaptersDeleteCmd.Flags().BoolVar(&adapterForceFlag, "force", false, "delete the adapter even if enforcers use it")
+}
@@ -7,28 +7,38 @@ import (
 	"github.com/spf13/cobra"
 	"gitlab.com/sdv9972401/casdoor-cli/helpers"
 	"gitlab.com/sdv9972401/casdoor-cli/utils"
-	"strings"
+	"path/filepath"
+	"time"
 )
 
-var groupNameFlag string
+var (
+	groupNameFlag          string
+	groupNewNameFlag       string
+	groupParentFlag        string
+	reassignToFlag         string
+	forceFlag              bool
+	groupAddDisplayFlag    string
+	groupAddTypeFlag       string
+	groupAddParentFlag     string
+	groupAddManagerFlag    string
+	groupUpdateDisplayFlag string
+	groupUpdateTypeFlag    string
+	groupUpdateParentFlag  string
+	groupUpdateManagerFlag string
+)
 
-var permissionsCmd = &cobra.Command{
+var groupsCmd = &cobra.Command{
 	Use:   "groups",
-	Short: "Manage Casdoor permissions",
-	Long:  "Manage Casdoor permissions",
+	Short: "Manage Casdoor groups",
+	Long:  "Manage Casdoor groups",
 }
 
-var permissionsListCmd = &cobra.Command{
+var groupsListCmd = &cobra.Command{
 	Use:   "list",
-	Short: "list Casdoor permissions",
-	Long:  "list Casdoor permissions",
+	Short: "list Casdoor groups",
+	Long:  "list Casdoor groups",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-			"editor",
-			"lector",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
@@ -39,76 +49,169 @@ var permissionsListCmd = &cobra.Command{
 	},
 }
 
-var permissionsAddCmd = &cobra.Command{
+var groupsAddCmd = &cobra.Command{
 	Use:   "add",
-	Short: "add Casdoor permission",
-	Long:  "add Casdoor permission",
+	Short: "add Casdoor group",
+	Long:  "add Casdoor group",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-			"editor",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
 		utils.Colorize(color.CyanString, "[ℹ] follow the prompts in order to create a new group")
 		userManager := helpers.NewUserManager(config)
-		err = userManager.AddGroup()
+		err = userManager.AddGroup(helpers.GroupFields{
+			DisplayName: groupAddDisplayFlag,
+			Type:        groupAddTypeFlag,
+			Parent:      groupAddParentFlag,
+			Manager:     groupAddManagerFlag,
+		})
 		if err != nil {
 			log.Fatal(err)
 		}
 	},
 }
 
-var permissionsDeleteCmd = &cobra.Command{
+var groupsDeleteCmd = &cobra.Command{
 	Use:   "delete",
-	Short: "delete Casdoor permission",
-	Long:  "delete Casdoor permission",
+	Short: "delete Casdoor group",
+	Long: `delete Casdoor group. A group which still has members or child groups is only deleted with
+--reassign-to, which moves them to another group, or with --force, which removes the group from its
+members and makes its child groups top groups. Their previous state is saved to a rollback file that
+can be applied with groups rollback.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
 		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+
+		userManager := helpers.NewUserManager(config)
+		plan, err := userManager.PlanGroupDeletion(groupNameFlag)
 		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !plan.IsEmpty() {
+			utils.Colorize(color.YellowString, "[⚠] group %v is still referenced by %d users and %d child groups:", groupNameFlag, len(plan.Members), len(plan.Children))
+			utils.PrintTables(plan.AffectedList())
+			if reassignToFlag == "" && !forceFlag {
+				utils.Colorize(color.RedString, "[x] use --reassign-to <group> or --force to delete it anyway")
+				return
+			}
+		}
+
+		if !userConfirms("[⚠] This will delete the group %v. Are you sure about that ? [y/N]: ", groupNameFlag) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
 			return
 		}
 
-		fmt.Print(color.YellowString("[⚠] This will delete the group %v. There is no undo. Are you sure about that ? [y/N]: ", groupNameFlag))
+		utils.Colorize(color.CyanString, "[ℹ] attempting to delete group %v", groupNameFlag)
+		casdoorFolder, _, err := getCasdoorFolderAndConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		rollbackPath := filepath.Join(casdoorFolder, "rollback", fmt.Sprintf("groups-delete-%s-%d.json", groupNameFlag, time.Now().Unix()))
+		err = userManager.ExecuteGroupDeletion(plan, reassignToFlag, rollbackPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
 
-		var userResponse string
-		_, err = fmt.Scanln(&userResponse)
+var groupsRollbackCmd = &cobra.Command{
+	Use:   "rollback <file>",
+	Short: "restore a deleted Casdoor group",
+	Long:  "restore a deleted Casdoor group, its memberships and its child groups from the rollback file written by groups delete",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
-		if strings.ToLower(userResponse) == "y" || strings.ToLower(userResponse) == "yes" {
-			utils.Colorize(color.CyanString, "[ℹ] attempting to delete group %v", groupNameFlag)
-			userManager := helpers.NewUserManager(config)
-			err := userManager.DeleteGroup(groupNameFlag)
-			if err != nil {
-				log.Fatal(err)
-			}
-		} else {
-			utils.Colorize(color.RedString, "[x] operation canceled")
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RollbackGroupDeletion(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var groupsUpdateCmd = &cobra.Command{
+	Use:   "update [name]",
+	Short: "update Casdoor group",
+	Long: `update Casdoor group. Without any field flag, every field is prompted for. Otherwise only the
+given fields are changed and all the other ones are preserved. Renaming a group with --new-name also
+updates its members and child groups.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 1 {
+			groupNameFlag = args[0]
+		}
+		if groupNameFlag == "" {
+			utils.Colorize(color.RedString, "[x] the name of the group is required")
+			return
+		}
+
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.GroupUpdate
+		if cmd.Flags().Changed("new-name") {
+			update.Name = &groupNewNameFlag
+		}
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &groupUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("type") {
+			update.Type = &groupUpdateTypeFlag
+		}
+		if cmd.Flags().Changed("parent") {
+			update.Parent = &groupUpdateParentFlag
+		}
+		if cmd.Flags().Changed("manager") {
+			update.Manager = &groupUpdateManagerFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateGroup(groupNameFlag, update)
+		if err != nil {
+			log.Fatal(err)
 		}
-		return
 	},
 }
 
-var permissionUpdateCmd = &cobra.Command{
-	Use:   "update",
-	Short: "update Casdoor permission",
-	Long:  "update Casdoor permission",
+var groupsTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "show the Casdoor group hierarchy",
+	Long:  "show the Casdoor group hierarchy of the organization, with the number of members of each group",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
 		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		userManager := helpers.NewUserManager(config)
+		tree, err := userManager.GetGroupTree()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(tree)
+	},
+}
+
+var groupsMoveCmd = &cobra.Command{
+	Use:   "move <group>",
+	Short: "move a Casdoor group under another group",
+	Long:  "move a Casdoor group under another group, or to the top of the hierarchy when --parent is empty",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
 		userManager := helpers.NewUserManager(config)
-		err = userManager.UpdateGroup(groupNameFlag)
+		err = userManager.MoveGroup(args[0], groupParentFlag)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -116,13 +219,27 @@ var permissionUpdateCmd = &cobra.Command{
 }
 
 func init() {
-	RootCmd.AddCommand(permissionsCmd)
-	permissionsCmd.AddCommand(permissionsListCmd)
-	permissionsCmd.AddCommand(permissionsAddCmd)
-	permissionsCmd.AddCommand(permissionsDeleteCmd)
-	permissionsCmd.AddCommand(permissionUpdateCmd)
-	permissionsDeleteCmd.Flags().StringVarP(&groupNameFlag, "name", "n", "", "name of the group")
-	permissionsDeleteCmd.MarkFlagRequired("name")
-	permissionUpdateCmd.Flags().StringVarP(&groupNameFlag, "name", "n", "", "name of the group")
-	permissionUpdateCmd.MarkFlagRequired("name")
+	RootCmd.AddCommand(groupsCmd)
+	groupsCmd.AddCommand(groupsListCmd)
+	groupsCmd.AddCommand(groupsAddCmd)
+	groupsCmd.AddCommand(groupsDeleteCmd)
+	groupsCmd.AddCommand(groupsUpdateCmd)
+	groupsCmd.AddCommand(groupsTreeCmd)
+	groupsCmd.AddCommand(groupsMoveCmd)
+	groupsCmd.AddCommand(groupsRollbackCmd)
+	groupsUpdateCmd.Flags().StringVarP(&groupNameFlag, "name", "n", "", "name of the group")
+	groupsUpdateCmd.Flags().StringVar(&groupNewNameFlag, "new-name", "", "rename the group")
+	groupsUpdateCmd.Flags().StringVar(&groupUpdateDisplayFlag, "display-name", "", "display name of the group")
+	groupsUpdateCmd.Flags().StringVar(&groupUpdateTypeFlag, "type", "", "type of the group (Virtual or Physical)")
+	groupsUpdateCmd.Flags().StringVar(&groupUpdateParentFlag, "parent", "", "parent group (empty for a top group)")
+	groupsUpdateCmd.Flags().StringVar(&groupUpdateManagerFlag, "manager", "", "manager of the group")
+	groupsAddCmd.Flags().StringVar(&groupAddDisplayFlag, "display-name", "", "display name of the group")
+	groupsAddCmd.Flags().StringVar(&groupAddTypeFlag, "type", "Virtual", "type of the group (Virtual or Physical)")
+	groupsAddCmd.Flags().StringVar(&groupAddParentFlag, "parent", "", "parent group (empty for a top group)")
+	groupsAddCmd.Flags().StringVar(&groupAddManagerFlag, "manager", "", "manager of the group")
+	groupsMoveCmd.Flags().StringVar(&groupParentFlag, "parent", "", "new parent group (empty for a top group)")
+	groupsDeleteCmd.Flags().StringVarP(&groupNameFlag, "name", "n", "", "name of the group")
+	groupsDeleteCmd.MarkFlagRequired("name")
+	groupsDeleteCmd.Flags().StringVar(&reassignToFlag, "reassign-to", "", "move the members and child groups to this group")
+	groupsDeleteCmd.Flags().BoolVar(&forceFlag, "force", false, "delete the group even if it has members or child groups")
 }
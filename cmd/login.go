@@ -83,7 +83,7 @@ func displayLoggedInUserInfo(tokenData *models.TokenData) {
 		"username": tokenData.IDTokenClaims.Name,
 		"id":       tokenData.IDTokenClaims.Sub,
 		"owner":    tokenData.IDTokenClaims.Owner,
-		"group":    strings.TrimPrefix(strings.Join(tokenData.IDTokenClaims.Groups, ", "), "casdoor-cli/"),
+		"group":    strings.Join(tokenData.IDTokenClaims.Groups, ", "),
 	}
 	utils.PrintTable(loggedInUserInfo)
 }
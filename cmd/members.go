@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var usersGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Manage the groups of a Casdoor user",
+	Long:  "Manage the groups of a Casdoor user",
+}
+
+var usersGroupsAddCmd = &cobra.Command{
+	Use:   "add <user> <group...>",
+	Short: "add a Casdoor user to groups",
+	Long:  "add a Casdoor user to groups",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddUserToGroups(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersGroupsRemoveCmd = &cobra.Command{
+	Use:   "remove <user> <group...>",
+	Short: "remove a Casdoor user from groups",
+	Long:  "remove a Casdoor user from groups",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemoveUserFromGroups(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersGroupsSetCmd = &cobra.Command{
+	Use:   "set <user> <group...>",
+	Short: "replace the groups of a Casdoor user",
+	Long:  "replace the groups of a Casdoor user",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetUserGroups(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var groupsMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage the members of a Casdoor group",
+	Long:  "Manage the members of a Casdoor group",
+}
+
+var groupsMembersListCmd = &cobra.Command{
+	Use:   "list <group>",
+	Short: "list the members of a Casdoor group",
+	Long:  "list the members of a Casdoor group",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		members, err := userManager.GetGroupMembers(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(members)
+	},
+}
+
+var groupsMembersAddCmd = &cobra.Command{
+	Use:   "add <group> <user...>",
+	Short: "add Casdoor users to a group",
+	Long:  "add Casdoor users to a group",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddGroupMembers(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var groupsMembersRemoveCmd = &cobra.Command{
+	Use:   "remove <group> <user...>",
+	Short: "remove Casdoor users from a group",
+	Long:  "remove Casdoor users from a group",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemoveGroupMembers(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	usersCmd.AddCommand(usersGroupsCmd)
+	usersGroupsCmd.AddCommand(usersGroupsAddCmd)
+	usersGroupsCmd.AddCommand(usersGroupsRemoveCmd)
+	usersGroupsCmd.AddCommand(usersGroupsSetCmd)
+	groupsCmd.AddCommand(groupsMembersCmd)
+	groupsMembersCmd.AddCommand(groupsMembersListCmd)
+	groupsMembersCmd.AddCommand(groupsMembersAddCmd)
+	groupsMembersCmd.AddCommand(groupsMembersRemoveCmd)
+}
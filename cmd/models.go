@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+)
+
+var (
+	modelFileFlag   string
+	modelDryRunFlag bool
+	modelForceFlag  bool
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Manage Casdoor Casbin models",
+	Long: `Manage the Casbin models stored in Casdoor. Models are edited as local model.conf files, which
+are validated before being uploaded:
+
+casdoor models get rbac > model.conf
+casdoor models diff rbac -f model.conf
+casdoor models apply rbac -f model.conf`,
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor Casbin models",
+	Long:  "list Casdoor Casbin models",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		models, err := userManager.GetModels()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(models)
+	},
+}
+
+var modelsGetCmd = &cobra.Command{
+	Use:   "get <model>",
+	Short: "print the text of a Casdoor Casbin model",
+	Long:  "print the text of a Casdoor Casbin model, in the model.conf format used by models apply",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		text, err := userManager.GetModelText(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(text)
+	},
+}
+
+var modelsApplyCmd = &cobra.Command{
+	Use:   "apply <model>",
+	Short: "create or update a Casdoor Casbin model from a file",
+	Long: `create or update a Casdoor Casbin model from a model.conf file. The request, policy, role,
+effect and matcher sections and the matcher expressions are validated locally before anything is
+uploaded. With --dry-run, the model is only validated and compared with the server copy.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.ApplyModel(args[0], modelFileFlag, modelDryRunFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var modelsDiffCmd = &cobra.Command{
+	Use:   "diff <model>",
+	Short: "compare a local model file with a Casdoor Casbin model",
+	Long:  "compare a local model file with a Casdoor Casbin model. The exit status is 1 when they differ",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		diff, err := userManager.DiffModel(args[0], modelFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !utils.HasChanges(diff) {
+			utils.Colorize(color.GreenString, "[✔] %v is identical to the %v model", modelFileFlag, args[0])
+			return
+		}
+		utils.PrintDiff(diff)
+		os.Exit(1)
+	},
+}
+
+var modelsDeleteCmd = &cobra.Command{
+	Use:   "delete <model>",
+	Short: "delete a Casdoor Casbin model",
+	Long:  "delete a Casdoor Casbin model. A model still used by permissions is only deleted with --force",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the model %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteModel(args[0], modelForceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(modelsCmd)
+	modelsCmd.AddCommand(modelsListCmd)
+	modelsCmd.AddCommand(modelsGetCmd)
+	modelsCmd.AddCommand(modelsApplyCmd)
+	modelsCmd.AddCommand(modelsDiffCmd)
+	modelsCmd.AddCommand(modelsDeleteCmd)
+	modelsApplyCmd.Flags().StringVarP(&modelFileFlag, "file", "f", "", "model.conf file to apply")
+	modelsApplyCmd.MarkFlagRequired("file")
+	modelsApplyCmd.Flags().BoolVar(&modelDryRunFlag, "dry-run", false, "only validate the model and show the changes")
+	modelsDiffCmd.Flags().StringVarP(&modelFileFlag, "file", "f", "", "model.conf file to compare")
+	modelsDiffCmd.MarkFlagRequired("file")
+	modelsDeleteCmd.Flags().BoolVar(&modelForceFlag, "force", false, "delete the model even if permissions use it")
+}
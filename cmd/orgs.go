@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	orgMfaFlag                      []string
+	accountItemVisibleFlag          bool
+	accountItemViewRuleFlag         string
+	accountItemModifyRuleFlag       string
+	orgAddDisplayFlag               string
+	orgAddWebsiteFlag               string
+	orgAddPasswordTypeFlag          string
+	orgAddPasswordOptionsFlag       []string
+	orgAddLanguagesFlag             []string
+	orgAddDefaultApplicationFlag    string
+	orgUpdateDisplayFlag            string
+	orgUpdateWebsiteFlag            string
+	orgUpdatePasswordTypeFlag       string
+	orgUpdatePasswordOptionsFlag    []string
+	orgUpdateLanguagesFlag          []string
+	orgUpdateDefaultApplicationFlag string
+)
+
+var orgsCmd = &cobra.Command{
+	Use:   "orgs",
+	Short: "Manage Casdoor organizations",
+	Long:  "Manage Casdoor organizations, along with their password policy, MFA items, languages and account items",
+}
+
+var orgsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor organizations",
+	Long:  "list Casdoor organizations",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		organizations, err := userManager.GetOrganizations()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(organizations)
+	},
+}
+
+var orgsGetCmd = &cobra.Command{
+	Use:   "get <organization>",
+	Short: "get a Casdoor organization",
+	Long:  "get a Casdoor organization along with its account items",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		organization, err := userManager.GetOrganization(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		accountItems, err := userManager.GetAccountItems(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(organization)
+		utils.PrintTables(accountItems)
+	},
+}
+
+var orgsAddCmd = &cobra.Command{
+	Use:   "add <organization>",
+	Short: "add a Casdoor organization",
+	Long: `add a Casdoor organization. Its MFA and account items are copied from the organization of the
+CLI, and can be changed afterwards with orgs update and orgs account-items set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddOrganization(args[0], helpers.OrganizationFields{
+			DisplayName:        orgAddDisplayFlag,
+			WebsiteUrl:         orgAddWebsiteFlag,
+			PasswordType:       orgAddPasswordTypeFlag,
+			PasswordOptions:    orgAddPasswordOptionsFlag,
+			Languages:          orgAddLanguagesFlag,
+			DefaultApplication: orgAddDefaultApplicationFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var orgsUpdateCmd = &cobra.Command{
+	Use:   "update <organization>",
+	Short: "update a Casdoor organization",
+	Long: `update a Casdoor organization. Only the given fields are changed, and list flags such as
+--password-option replace the whole list, except --mfa which sets the rule of a single MFA item,
+such as --mfa email=Required.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.OrganizationUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &orgUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("website") {
+			update.WebsiteUrl = &orgUpdateWebsiteFlag
+		}
+		if cmd.Flags().Changed("password-type") {
+			update.PasswordType = &orgUpdatePasswordTypeFlag
+		}
+		if cmd.Flags().Changed("password-option") {
+			update.PasswordOptions = &orgUpdatePasswordOptionsFlag
+		}
+		if cmd.Flags().Changed("language") {
+			update.Languages = &orgUpdateLanguagesFlag
+		}
+		if cmd.Flags().Changed("default-application") {
+			update.DefaultApplication = &orgUpdateDefaultApplicationFlag
+		}
+		update.MfaItems, err = helpers.ParseProperties(orgMfaFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateOrganization(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var orgsDeleteCmd = &cobra.Command{
+	Use:   "delete <organization>",
+	Short: "delete a Casdoor organization",
+	Long:  "delete a Casdoor organization. The organization the CLI is logged in to cannot be deleted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		tokenData, err := utils.KeyringToTokenData()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !userConfirms("[⚠] This will delete the organization %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteOrganization(args[0], tokenData.IDTokenClaims.Owner)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var orgsAccountItemsCmd = &cobra.Command{
+	Use:   "account-items",
+	Short: "Manage the account items of a Casdoor organization",
+	Long:  "Manage the account items of a Casdoor organization, which define the visibility of the user fields",
+}
+
+var orgsAccountItemsSetCmd = &cobra.Command{
+	Use:   "set <organization> <item>",
+	Short: "set the visibility rules of an account item",
+	Long: `set the visibility rules of an account item, such as "Phone". Only the given rules are changed:
+--view-rule is Public, Self or Admin, and --modify-rule is Self, Admin or Immutable.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.AccountItemUpdate
+		if cmd.Flags().Changed("visible") {
+			update.Visible = &accountItemVisibleFlag
+		}
+		if cmd.Flags().Changed("view-rule") {
+			update.ViewRule = &accountItemViewRuleFlag
+		}
+		if cmd.Flags().Changed("modify-rule") {
+			update.ModifyRule = &accountItemModifyRuleFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateAccountItem(args[0], args[1], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(orgsCmd)
+	orgsCmd.AddCommand(orgsListCmd)
+	orgsCmd.AddCommand(orgsGetCmd)
+	orgsCmd.AddCommand(orgsAddCmd)
+	orgsCmd.AddCommand(orgsUpdateCmd)
+	orgsCmd.AddCommand(orgsDeleteCmd)
+	orgsCmd.AddCommand(orgsAccountItemsCmd)
+	orgsAccountItemsCmd.AddCommand(orgsAccountItemsSetCmd)
+	orgsUpdateCmd.Flags().StringVar(&orgUpdateDisplayFlag, "display-name", "", "display name of the organization")
+	orgsUpdateCmd.Flags().StringVar(&orgUpdateWebsiteFlag, "website", "", "website URL of the organization")
+	orgsUpdateCmd.Flags().StringVar(&orgUpdatePasswordTypeFlag, "password-type", "", "password hashing type (plain, salt, sha512-salt, md5-salt, bcrypt, pbkdf2-salt or argon2id)")
+	orgsUpdateCmd.Flags().StringArrayVar(&orgUpdatePasswordOptionsFlag, "password-option", nil, "password complexity option (AtLeast6, AtLeast8, Aa123, SpecialChar or NoRepeat) (repeatable)")
+	orgsUpdateCmd.Flags().StringArrayVar(&orgUpdateLanguagesFlag, "language", nil, "language offered to the users, such as en (repeatable)")
+	orgsUpdateCmd.Flags().StringVar(&orgUpdateDefaultApplicationFlag, "default-application", "", "default application of the organization")
+	orgsUpdateCmd.Flags().StringArrayVar(&orgMfaFlag, "mfa", nil, "name=rule of an MFA item, the rule being Optional, Prompted or Required (repeatable)")
+	orgsAddCmd.Flags().StringVar(&orgAddDisplayFlag, "display-name", "", "display name of the organization")
+	orgsAddCmd.Flags().StringVar(&orgAddWebsiteFlag, "website", "", "website URL of the organization")
+	orgsAddCmd.Flags().StringVar(&orgAddPasswordTypeFlag, "password-type", "bcrypt", "password hashing type (plain, salt, sha512-salt, md5-salt, bcrypt, pbkdf2-salt or argon2id)")
+	orgsAddCmd.Flags().StringArrayVar(&orgAddPasswordOptionsFlag, "password-option", []string{"AtLeast6"}, "password complexity option (AtLeast6, AtLeast8, Aa123, SpecialChar or NoRepeat) (repeatable)")
+	orgsAddCmd.Flags().StringArrayVar(&orgAddLanguagesFlag, "language", []string{"en"}, "language offered to the users, such as en (repeatable)")
+	orgsAddCmd.Flags().StringVar(&orgAddDefaultApplicationFlag, "default-application", "", "default application of the organization")
+	orgsAccountItemsSetCmd.Flags().BoolVar(&accountItemVisibleFlag, "visible", true, "whether the account item is visible")
+	orgsAccountItemsSetCmd.Flags().StringVar(&accountItemViewRuleFlag, "view-rule", "", "who can view the account item (Public, Self or Admin)")
+	orgsAccountItemsSetCmd.Flags().StringVar(&accountItemModifyRuleFlag, "modify-rule", "", "who can modify the account item (Self, Admin or Immutable)")
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	permissionRoleFilterFlag         string
+	permissionStateFilterFlag        string
+	permissionAddDisplayFlag         string
+	permissionAddDescriptionFlag     string
+	permissionAddUsersFlag           []string
+	permissionAddGroupsFlag          []string
+	permissionAddRolesFlag           []string
+	permissionAddDomainsFlag         []string
+	permissionAddModelFlag           string
+	permissionAddAdapterFlag         string
+	permissionAddResourceTypeFlag    string
+	permissionAddResourcesFlag       []string
+	permissionAddActionsFlag         []string
+	permissionAddEffectFlag          string
+	permissionAddEnabledFlag         bool
+	permissionAddStateFlag           string
+	permissionUpdateDisplayFlag      string
+	permissionUpdateDescriptionFlag  string
+	permissionUpdateUsersFlag        []string
+	permissionUpdateGroupsFlag       []string
+	permissionUpdateRolesFlag        []string
+	permissionUpdateDomainsFlag      []string
+	permissionUpdateModelFlag        string
+	permissionUpdateAdapterFlag      string
+	permissionUpdateResourceTypeFlag string
+	permissionUpdateResourcesFlag    []string
+	permissionUpdateActionsFlag      []string
+	permissionUpdateEffectFlag       string
+	permissionUpdateEnabledFlag      bool
+	permissionUpdateStateFlag        string
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Manage Casdoor permissions",
+	Long: `Manage Casdoor permissions. A permission grants (or denies, with the Deny effect) actions on
+resources to users, groups and roles. Permissions submitted for approval stay Pending until they are
+approved or rejected.`,
+}
+
+var permissionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor permissions",
+	Long:  "list Casdoor permissions, optionally only the ones granted to a role (--role) or in an approval state (--state)",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		permissions, err := userManager.GetPermissions(permissionRoleFilterFlag, permissionStateFilterFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(permissions)
+	},
+}
+
+var permissionsGetCmd = &cobra.Command{
+	Use:   "get <permission>",
+	Short: "get a Casdoor permission",
+	Long:  "get a Casdoor permission",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		permission, err := userManager.GetPermission(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(permission)
+	},
+}
+
+var permissionsAddCmd = &cobra.Command{
+	Use:   "add <permission>",
+	Short: "add a Casdoor permission",
+	Long:  "add a Casdoor permission. Use --state Pending to submit it for approval",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		submitter, err := currentUserId()
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddPermission(args[0], helpers.PermissionFields{
+			DisplayName:  permissionAddDisplayFlag,
+			Description:  permissionAddDescriptionFlag,
+			Users:        permissionAddUsersFlag,
+			Groups:       permissionAddGroupsFlag,
+			Roles:        permissionAddRolesFlag,
+			Domains:      permissionAddDomainsFlag,
+			Model:        permissionAddModelFlag,
+			Adapter:      permissionAddAdapterFlag,
+			ResourceType: permissionAddResourceTypeFlag,
+			Resources:    permissionAddResourcesFlag,
+			Actions:      permissionAddActionsFlag,
+			Effect:       permissionAddEffectFlag,
+			Enabled:      permissionAddEnabledFlag,
+			State:        permissionAddStateFlag,
+		}, submitter)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var permissionsUpdateCmd = &cobra.Command{
+	Use:   "update <permission>",
+	Short: "update a Casdoor permission",
+	Long: `update a Casdoor permission. Only the given fields are changed, and list flags such as --user or
+--action replace the whole list.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.PermissionUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &permissionUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("description") {
+			update.Description = &permissionUpdateDescriptionFlag
+		}
+		if cmd.Flags().Changed("user") {
+			update.Users = &permissionUpdateUsersFlag
+		}
+		if cmd.Flags().Changed("group") {
+			update.Groups = &permissionUpdateGroupsFlag
+		}
+		if cmd.Flags().Changed("role") {
+			update.Roles = &permissionUpdateRolesFlag
+		}
+		if cmd.Flags().Changed("domain") {
+			update.Domains = &permissionUpdateDomainsFlag
+		}
+		if cmd.Flags().Changed("model") {
+			update.Model = &permissionUpdateModelFlag
+		}
+		if cmd.Flags().Changed("adapter") {
+			update.Adapter = &permissionUpdateAdapterFlag
+		}
+		if cmd.Flags().Changed("resource-type") {
+			update.ResourceType = &permissionUpdateResourceTypeFlag
+		}
+		if cmd.Flags().Changed("resource") {
+			update.Resources = &permissionUpdateResourcesFlag
+		}
+		if cmd.Flags().Changed("action") {
+			update.Actions = &permissionUpdateActionsFlag
+		}
+		if cmd.Flags().Changed("effect") {
+			update.Effect = &permissionUpdateEffectFlag
+		}
+		if cmd.Flags().Changed("enabled") {
+			update.Enabled = &permissionUpdateEnabledFlag
+		}
+		if cmd.Flags().Changed("state") {
+			update.State = &permissionUpdateStateFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdatePermission(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var permissionsDeleteCmd = &cobra.Command{
+	Use:   "delete <permission>",
+	Short: "delete a Casdoor permission",
+	Long:  "delete a Casdoor permission",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the permission %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeletePermission(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var permissionsApproveCmd = &cobra.Command{
+	Use:   "approve <permission>",
+	Short: "approve a Casdoor permission pending approval",
+	Long:  "approve a Casdoor permission pending approval. You are recorded as its approver",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reviewPermission(cmd, args[0], true)
+	},
+}
+
+var permissionsRejectCmd = &cobra.Command{
+	Use:   "reject <permission>",
+	Short: "reject a Casdoor permission pending approval",
+	Long:  "reject a Casdoor permission pending approval. You are recorded as its approver",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reviewPermission(cmd, args[0], false)
+	},
+}
+
+func reviewPermission(cmd *cobra.Command, name string, approve bool) {
+	config, err := checkLoggedInAndGetConfig(cmd)
+	if err != nil {
+		return
+	}
+	approver, err := currentUserId()
+	if err != nil {
+		log.Fatal(err)
+	}
+	userManager := helpers.NewUserManager(config)
+	err = userManager.ReviewPermission(name, approve, approver)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// currentUserId returns the "<owner>/<name>" id of the logged-in user.
+func currentUserId() (string, error) {
+	tokenData, err := utils.KeyringToTokenData()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", tokenData.IDTokenClaims.Owner, tokenData.IDTokenClaims.Name), nil
+}
+
+func init() {
+	RootCmd.AddCommand(permissionsCmd)
+	permissionsCmd.AddCommand(permissionsListCmd)
+	permissionsCmd.AddCommand(permissionsGetCmd)
+	permissionsCmd.AddCommand(permissionsAddCmd)
+	permissionsCmd.AddCommand(permissionsUpdateCmd)
+	permissionsCmd.AddCommand(permissionsDeleteCmd)
+	permissionsCmd.AddCommand(permissionsApproveCmd)
+	permissionsCmd.AddCommand(permissionsRejectCmd)
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateDisplayFlag, "display-name", "", "display name of the permission")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateDescriptionFlag, "description", "", "description of the permission")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateUsersFlag, "user", nil, "user granted the permission, * for all (repeatable)")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateGroupsFlag, "group", nil, "group granted the permission (repeatable)")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateRolesFlag, "role", nil, "role granted the permission (repeatable)")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateDomainsFlag, "domain", nil, "domain of the permission (repeatable)")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateModelFlag, "model", "", "Casbin model of the permission")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateAdapterFlag, "adapter", "", "adapter storing the policies of the permission")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateResourceTypeFlag, "resource-type", "", "type of the resources (Application, TreeNode, Custom or API)")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateResourcesFlag, "resource", nil, "resource of the permission (repeatable)")
+	permissionsUpdateCmd.Flags().StringArrayVar(&permissionUpdateActionsFlag, "action", nil, "action allowed on the resources (repeatable)")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateEffectFlag, "effect", "", "effect of the permission (Allow or Deny)")
+	permissionsUpdateCmd.Flags().BoolVar(&permissionUpdateEnabledFlag, "enabled", true, "whether the permission is enabled")
+	permissionsUpdateCmd.Flags().StringVar(&permissionUpdateStateFlag, "state", "", "approval state of the permission (Pending, Approved or Rejected)")
+	permissionsListCmd.Flags().StringVar(&permissionRoleFilterFlag, "role", "", "only list the permissions of this role")
+	permissionsListCmd.Flags().StringVar(&permissionStateFilterFlag, "state", "", "only list the permissions in this state (Pending, Approved or Rejected)")
+	permissionsAddCmd.Flags().StringVar(&permissionAddDisplayFlag, "display-name", "", "display name of the permission")
+	permissionsAddCmd.Flags().StringVar(&permissionAddDescriptionFlag, "description", "", "description of the permission")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddUsersFlag, "user", nil, "user granted the permission, * for all (repeatable)")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddGroupsFlag, "group", nil, "group granted the permission (repeatable)")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddRolesFlag, "role", nil, "role granted the permission (repeatable)")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddDomainsFlag, "domain", nil, "domain of the permission (repeatable)")
+	permissionsAddCmd.Flags().StringVar(&permissionAddModelFlag, "model", "", "Casbin model of the permission")
+	permissionsAddCmd.Flags().StringVar(&permissionAddAdapterFlag, "adapter", "", "adapter storing the policies of the permission")
+	permissionsAddCmd.Flags().StringVar(&permissionAddResourceTypeFlag, "resource-type", "Application", "type of the resources (Application, TreeNode, Custom or API)")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddResourcesFlag, "resource", nil, "resource of the permission (repeatable)")
+	permissionsAddCmd.Flags().StringArrayVar(&permissionAddActionsFlag, "action", nil, "action allowed on the resources (repeatable)")
+	permissionsAddCmd.Flags().StringVar(&permissionAddEffectFlag, "effect", "Allow", "effect of the permission (Allow or Deny)")
+	permissionsAddCmd.Flags().BoolVar(&permissionAddEnabledFlag, "enabled", true, "whether the permission is enabled")
+	permissionsAddCmd.Flags().StringVar(&permissionAddStateFlag, "state", helpers.PermissionApproved, "approval state of the permission (Pending, Approved or Rejected)")
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+)
+
+var (
+	policiesFormatFlag string
+	policiesFileFlag   string
+	policiesPruneFlag  bool
+	policiesDryRunFlag bool
+)
+
+var policiesCmd = &cobra.Command{
+	Use:   "policies",
+	Short: "Manage the Casbin policies of Casdoor enforcers",
+	Long: `Manage the Casbin policies of Casdoor enforcers. Policies are written as Casbin policy CSV lines,
+such as "p, alice, data1, read" or "g, alice, admin".`,
+}
+
+var policiesListCmd = &cobra.Command{
+	Use:   "list <enforcer>",
+	Short: "list the policies of a Casdoor enforcer",
+	Long:  "list the policies of a Casdoor enforcer",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		policies, err := userManager.GetPolicies(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(policies)
+	},
+}
+
+var policiesAddCmd = &cobra.Command{
+	Use:   "add <enforcer> <policy>",
+	Short: "add a policy to a Casdoor enforcer",
+	Long:  `add a policy to a Casdoor enforcer, such as casdoor policies add my-enforcer "p, alice, data1, read"`,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddPolicy(args[0], strings.Join(args[1:], " "))
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var policiesRemoveCmd = &cobra.Command{
+	Use:   "remove <enforcer> <policy>",
+	Short: "remove a policy from a Casdoor enforcer",
+	Long:  `remove a policy from a Casdoor enforcer, such as casdoor policies remove my-enforcer "p, alice, data1, read"`,
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemovePolicy(args[0], strings.Join(args[1:], " "))
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var policiesExportCmd = &cobra.Command{
+	Use:   "export <enforcer>",
+	Short: "export the policies of a Casdoor enforcer",
+	Long:  "export the policies of a Casdoor enforcer as a Casbin policy CSV file, or to the standard output without --file",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkPoliciesFormat(); err != nil {
+			log.Fatal(err)
+		}
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+
+		if policiesFileFlag == "" {
+			_, err = userManager.ExportPolicies(args[0], os.Stdout)
+			if err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		file, err := os.OpenFile(policiesFileFlag, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		count, err := userManager.ExportPolicies(args[0], file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.Colorize(color.GreenString, "[✔] %d policies exported to %v", count, policiesFileFlag)
+	},
+}
+
+var policiesImportCmd = &cobra.Command{
+	Use:   "import <enforcer>",
+	Short: "import policies into a Casdoor enforcer",
+	Long: `import a Casbin policy CSV file into a Casdoor enforcer. Only the policies missing from the
+enforcer are added, and with --prune, the policies absent from the file are removed. With --dry-run,
+the changes are only shown.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := checkPoliciesFormat(); err != nil {
+			log.Fatal(err)
+		}
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.ImportPolicies(args[0], policiesFileFlag, policiesPruneFlag, policiesDryRunFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func checkPoliciesFormat() error {
+	if policiesFormatFlag != "csv" {
+		return fmt.Errorf("unsupported format %v (only csv is supported)", policiesFormatFlag)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(policiesCmd)
+	policiesCmd.AddCommand(policiesListCmd)
+	policiesCmd.AddCommand(policiesAddCmd)
+	policiesCmd.AddCommand(policiesRemoveCmd)
+	policiesCmd.AddCommand(policiesExportCmd)
+	policiesCmd.AddCommand(policiesImportCmd)
+	policiesExportCmd.Flags().StringVar(&policiesFormatFlag, "format", "csv", "format of the policies (csv)")
+	policiesExportCmd.Flags().StringVarP(&policiesFileFlag, "file", "f", "", "file to export the policies to")
+	policiesImportCmd.Flags().StringVar(&policiesFormatFlag, "format", "csv", "format of the policies (csv)")
+	policiesImportCmd.Flags().StringVarP(&policiesFileFlag, "file", "f", "", "file to import the policies from")
+	policiesImportCmd.MarkFlagRequired("file")
+	policiesImportCmd.Flags().BoolVar(&policiesPruneFlag, "prune", false, "remove the policies absent from the file")
+	policiesImportCmd.Flags().BoolVar(&policiesDryRunFlag, "dry-run", false, "only show the changes")
+}
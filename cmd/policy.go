@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/logger"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+)
+
+var (
+	policyTestFileFlag       string
+	policyTestPermissionFlag string
+	policyTestModelFlag      string
+	policyEvalModelFlag      string
+	policyEvalPolicyFlag     string
+	policyEvalExplainFlag    bool
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Manage and test authorization policies",
+	Long: `Manage the command authorization policy, and test Casdoor authorization policies. In local authorization mode, the policy maps command
+paths such as users.delete to the groups allowed to run them. It is read from ~/.casdoor-cli/policy.yaml,
+or from the file given by policy_file in the config, and defaults to the built-in roles otherwise:
+
+rules:
+  - command: users.set-password
+    allow: [administrator, helpdesk]
+  - command: users.*
+    allow: [administrator]
+  - command: "**"
+    allow: [administrator]
+
+The first rule matching a command applies. "*" matches one segment of the path, "**" any number of
+segments, and an allow entry of "*" allows every logged-in user.
+
+Casdoor permissions and models can be checked against test cases with policy test, and requests can
+be evaluated offline with policy eval.`,
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "run authorization test cases against Casdoor",
+	Long: `run the authorization test cases of a YAML file through Casdoor BatchEnforce, against the permission
+or model given in the file or with --permission or --model:
+
+permission: my-org/my-permission
+cases:
+  - name: editors cannot delete users
+    subject: my-org/bob
+    object: users
+    action: delete
+    expected: deny
+
+The exit status is 1 when a case doesn't get the expected outcome, so that policy changes can be
+checked in CI.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		suite, err := helpers.LoadPolicyTestSuite(policyTestFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if cmd.Flags().Changed("permission") || cmd.Flags().Changed("model") {
+			suite.Permission = policyTestPermissionFlag
+			suite.Model = policyTestModelFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		results, err := userManager.RunPolicyTests(suite)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		failed := 0
+		var resultList []map[string]interface{}
+		for _, result := range results {
+			actual, status := "deny", "PASS"
+			if result.Allowed {
+				actual = "allow"
+			}
+			if !result.Passed {
+				status = "FAIL"
+				failed++
+			}
+			resultList = append(resultList, map[string]interface{}{
+				"Case":     result.Case.Name,
+				"Subject":  result.Case.Subject,
+				"Object":   result.Case.Object,
+				"Action":   result.Case.Action,
+				"Expected": result.Case.Expected,
+				"Actual":   actual,
+				"Result":   status,
+			})
+		}
+		utils.PrintTables(resultList)
+
+		if failed > 0 {
+			utils.Colorize(color.RedString, "[x] %d of %d cases failed", failed, len(results))
+			os.Exit(1)
+		}
+		utils.Colorize(color.GreenString, "[✔] all %d cases passed", len(results))
+	},
+}
+
+var policyEvalCmd = &cobra.Command{
+	Use:   "eval <sub> <obj> <act>",
+	Short: "evaluate an authorization request offline",
+	Long: `evaluate an authorization request offline, with a Casbin model file and a Casbin policy CSV file
+such as the ones written by models get and policies export. Nothing is sent to Casdoor, so changes to
+a model or to policies can be simulated before they are applied:
+
+casdoor models get rbac > model.conf
+casdoor policies export my-enforcer -f policy.csv
+casdoor policy eval --model model.conf --policy policy.csv alice data1 read --explain
+
+The request holds one value for each token of the request definition of the model, such as
+alice domain1 data1 read for r = sub, dom, obj, act. With --explain, the policy line which decided
+the outcome and the role inheritance chain leading to it are shown. The exit status is 1 when the
+request is denied.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// a missing --model is reported by the required flags check
+		if policyEvalModelFlag == "" {
+			return nil
+		}
+		tokens, err := helpers.ModelRequestTokens(policyEvalModelFlag)
+		if err != nil {
+			return err
+		}
+		if len(args) != len(tokens) {
+			return fmt.Errorf("the request definition of %v expects %d values (%v), received %d", policyEvalModelFlag, len(tokens), strings.Join(tokens, ", "), len(args))
+		}
+		return nil
+	},
+	// the evaluation is offline, so the endpoint check of the root command is skipped
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logger.ToggleDebug(debug)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		evaluation, err := helpers.EvaluatePolicy(policyEvalModelFlag, policyEvalPolicyFlag, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if policyEvalExplainFlag {
+			matched := "none"
+			if evaluation.Matched != nil {
+				matched = helpers.FormatPolicyLine(evaluation.Matched)
+			}
+			utils.Colorize(color.CyanString, "[ℹ] request: %v", strings.Join(args, ", "))
+			utils.Colorize(color.CyanString, "[ℹ] matched policy: %v", matched)
+			if evaluation.RoleChain != nil {
+				utils.Colorize(color.CyanString, "[ℹ] role chain: %v", strings.Join(evaluation.RoleChain, " -> "))
+			}
+			if evaluation.Roles != nil {
+				utils.Colorize(color.CyanString, "[ℹ] roles of %v: %v", args[0], strings.Join(evaluation.Roles, ", "))
+			}
+		}
+
+		if !evaluation.Allowed {
+			utils.Colorize(color.RedString, "[x] denied")
+			os.Exit(1)
+		}
+		utils.Colorize(color.GreenString, "[✔] allowed")
+	},
+}
+
+var policyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "show the effective authorization policy",
+	Long:  "show the rules of the authorization policy and the rule which applies to every command",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := initCasdoorConfig()
+		if err != nil {
+			log.Fatal(err)
+		}
+		policy, err := loadPolicy(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		utils.Colorize(color.CyanString, "[ℹ] policy source: %v", policy.Source)
+		var rules []map[string]interface{}
+		for i, rule := range policy.Rules {
+			rules = append(rules, map[string]interface{}{
+				"Order":   i + 1,
+				"Command": rule.Command,
+				"Allow":   strings.Join(rule.Allow, ", "),
+			})
+		}
+		utils.PrintTables(rules)
+
+		utils.Colorize(color.CyanString, "[ℹ] effective rules:")
+		var effective []map[string]interface{}
+		for _, path := range authorizedCommandPaths(RootCmd) {
+			rule, ok := policy.Match(path)
+			allow := "nobody"
+			if ok {
+				allow = strings.Join(rule.Allow, ", ")
+			}
+			effective = append(effective, map[string]interface{}{
+				"Command": path,
+				"Rule":    rule.Command,
+				"Allow":   allow,
+			})
+		}
+		utils.PrintTables(effective)
+	},
+}
+
+// unauthorizedCommands are the commands, with their subcommands, which don't go through the authorization policy.
+var unauthorizedCommands = []string{"auth", "completion", "help", "login", "logout", "policy.eval", "policy.show"}
+
+// authorizedCommandPaths returns the paths of every runnable command subject to the policy.
+func authorizedCommandPaths(cmd *cobra.Command) []string {
+	var paths []string
+	for _, child := range cmd.Commands() {
+		if containsCommand(unauthorizedCommands, commandPath(child)) {
+			continue
+		}
+		if child.Runnable() {
+			paths = append(paths, commandPath(child))
+		}
+		paths = append(paths, authorizedCommandPaths(child)...)
+	}
+	return paths
+}
+
+func containsCommand(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	RootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyShowCmd)
+	policyCmd.AddCommand(policyTestCmd)
+	policyCmd.AddCommand(policyEvalCmd)
+	policyEvalCmd.Flags().StringVar(&policyEvalModelFlag, "model", "", "Casbin model file")
+	policyEvalCmd.MarkFlagRequired("model")
+	policyEvalCmd.Flags().StringVar(&policyEvalPolicyFlag, "policy", "", "Casbin policy CSV file")
+	policyEvalCmd.MarkFlagRequired("policy")
+	policyEvalCmd.Flags().BoolVar(&policyEvalExplainFlag, "explain", false, "show the matched policy and the role inheritance chain")
+	policyTestCmd.Flags().StringVarP(&policyTestFileFlag, "file", "f", "", "YAML file of test cases")
+	policyTestCmd.MarkFlagRequired("file")
+	policyTestCmd.Flags().StringVar(&policyTestPermissionFlag, "permission", "", "permission to test against, instead of the one of the file")
+	policyTestCmd.Flags().StringVar(&policyTestModelFlag, "model", "", "model to test against, instead of the one of the file")
+}
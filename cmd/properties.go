@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var usersPropsCmd = &cobra.Command{
+	Use:   "props",
+	Short: "Manage the custom properties of a Casdoor user",
+	Long:  "Manage the custom properties of a Casdoor user, such as cost centre, employee ID or badge number",
+}
+
+var usersPropsGetCmd = &cobra.Command{
+	Use:   "get <user> [key...]",
+	Short: "get the custom properties of a Casdoor user",
+	Long:  "get the custom properties of a Casdoor user. All properties are shown when no key is given",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		properties, err := userManager.GetUserProperties(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(properties)
+	},
+}
+
+var usersPropsSetCmd = &cobra.Command{
+	Use:   "set <user> <key=value...>",
+	Short: "set custom properties of a Casdoor user",
+	Long:  "set custom properties of a Casdoor user. Other properties are kept",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		properties, err := helpers.ParseProperties(args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetUserProperties(args[0], properties)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersPropsUnsetCmd = &cobra.Command{
+	Use:   "unset <user> <key...>",
+	Short: "remove custom properties of a Casdoor user",
+	Long:  "remove custom properties of a Casdoor user",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UnsetUserProperties(args[0], args[1:])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	usersCmd.AddCommand(usersPropsCmd)
+	usersPropsCmd.AddCommand(usersPropsGetCmd)
+	usersPropsCmd.AddCommand(usersPropsSetCmd)
+	usersPropsCmd.AddCommand(usersPropsUnsetCmd)
+}
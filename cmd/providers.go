@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	providerCategoryFilterFlag     string
+	providerTypeFilterFlag         string
+	providerCategoryFlag           string
+	providerTypeFlag               string
+	providerShowSecretsFlag        bool
+	providerForceFlag              bool
+	providerAddDisplayFlag         string
+	providerAddSubTypeFlag         string
+	providerAddClientIdFlag        string
+	providerAddClientSecretFlag    bool
+	providerAddHostFlag            string
+	providerAddPortFlag            int
+	providerAddEndpointFlag        string
+	providerAddRegionFlag          string
+	providerAddSignNameFlag        string
+	providerAddTemplateFlag        string
+	providerAddBucketFlag          string
+	providerAddDomainFlag          string
+	providerUpdateDisplayFlag      string
+	providerUpdateSubTypeFlag      string
+	providerUpdateClientIdFlag     string
+	providerUpdateClientSecretFlag bool
+	providerUpdateHostFlag         string
+	providerUpdatePortFlag         int
+	providerUpdateEndpointFlag     string
+	providerUpdateRegionFlag       string
+	providerUpdateSignNameFlag     string
+	providerUpdateTemplateFlag     string
+	providerUpdateBucketFlag       string
+	providerUpdateDomainFlag       string
+)
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Manage Casdoor providers",
+	Long:  "Manage Casdoor providers, such as OAuth, Email, SMS, Storage or Captcha providers",
+}
+
+var providersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor providers",
+	Long:  "list the Casdoor providers of the organization. Secrets are masked unless --show-secrets is given",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if err = checkProviderSecrets(); err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		providers, err := userManager.GetProviders(providerCategoryFilterFlag, providerTypeFilterFlag, providerShowSecretsFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(providers)
+	},
+}
+
+var providersGetCmd = &cobra.Command{
+	Use:   "get <provider>",
+	Short: "get a Casdoor provider",
+	Long:  "get a Casdoor provider. Its secrets are masked unless --show-secrets is given",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if err = checkProviderSecrets(); err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		provider, err := userManager.GetProvider(args[0], providerShowSecretsFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(provider)
+	},
+}
+
+var providersAddCmd = &cobra.Command{
+	Use:   "add <provider>",
+	Short: "add a Casdoor provider",
+	Long: `add a Casdoor provider of a --category and a --type, such as --category Email --type Default for
+an SMTP server. The client secret, which is the SMTP password of Email providers, is prompted for with
+--client-secret.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		clientSecret := ""
+		if providerAddClientSecretFlag {
+			clientSecret, err = promptProviderSecret()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddProvider(args[0], helpers.ProviderFields{
+			DisplayName:  providerAddDisplayFlag,
+			Category:     providerCategoryFlag,
+			Type:         providerTypeFlag,
+			SubType:      providerAddSubTypeFlag,
+			ClientId:     providerAddClientIdFlag,
+			ClientSecret: clientSecret,
+			Host:         providerAddHostFlag,
+			Port:         providerAddPortFlag,
+			Endpoint:     providerAddEndpointFlag,
+			RegionId:     providerAddRegionFlag,
+			SignName:     providerAddSignNameFlag,
+			TemplateCode: providerAddTemplateFlag,
+			Bucket:       providerAddBucketFlag,
+			Domain:       providerAddDomainFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var providersUpdateCmd = &cobra.Command{
+	Use:   "update <provider>",
+	Short: "update a Casdoor provider",
+	Long: `update a Casdoor provider. Only the given fields are changed, and a new client secret is prompted
+for with --client-secret.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.ProviderUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &providerUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("sub-type") {
+			update.SubType = &providerUpdateSubTypeFlag
+		}
+		if cmd.Flags().Changed("client-id") {
+			update.ClientId = &providerUpdateClientIdFlag
+		}
+		if providerUpdateClientSecretFlag {
+			clientSecret, err := promptProviderSecret()
+			if err != nil {
+				log.Fatal(err)
+			}
+			update.ClientSecret = &clientSecret
+		}
+		if cmd.Flags().Changed("host") {
+			update.Host = &providerUpdateHostFlag
+		}
+		if cmd.Flags().Changed("port") {
+			update.Port = &providerUpdatePortFlag
+		}
+		if cmd.Flags().Changed("endpoint") {
+			update.Endpoint = &providerUpdateEndpointFlag
+		}
+		if cmd.Flags().Changed("region") {
+			update.RegionId = &providerUpdateRegionFlag
+		}
+		if cmd.Flags().Changed("sign-name") {
+			update.SignName = &providerUpdateSignNameFlag
+		}
+		if cmd.Flags().Changed("template-code") {
+			update.TemplateCode = &providerUpdateTemplateFlag
+		}
+		if cmd.Flags().Changed("bucket") {
+			update.Bucket = &providerUpdateBucketFlag
+		}
+		if cmd.Flags().Changed("domain") {
+			update.Domain = &providerUpdateDomainFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateProvider(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var providersDeleteCmd = &cobra.Command{
+	Use:   "delete <provider>",
+	Short: "delete a Casdoor provider",
+	Long:  "delete a Casdoor provider. A provider still used by applications is only deleted with --force",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the provider %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteProvider(args[0], providerForceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var providersTestCmd = &cobra.Command{
+	Use:   "test <provider> <receiver>",
+	Short: "send a test message through a Casdoor provider",
+	Long: `send a test message through an Email provider to an email address, or through an SMS provider to
+a phone number including its country code, such as +33612345678. Email providers are tested by
+connecting to their SMTP server directly with the host, port, username and password of the provider.
+SMS messages are sent by Casdoor, which only selects the providers of admin by name, so only these
+SMS providers can be tested.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.TestProvider(args[0], args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// checkProviderSecrets authorizes --show-secrets on its own, like apps list/get do
+func checkProviderSecrets() error {
+	if !providerShowSecretsFlag {
+		return nil
+	}
+	_, err := checkLoggedInAndGetConfigForPath("providers.secrets")
+	return err
+}
+
+func promptProviderSecret() (string, error) {
+	secretPrompt := promptui.Prompt{
+		Label: "Client secret",
+		Mask:  '*',
+	}
+	return secretPrompt.Run()
+}
+
+func init() {
+	RootCmd.AddCommand(providersCmd)
+	providersCmd.AddCommand(providersListCmd)
+	providersCmd.AddCommand(providersGetCmd)
+	providersCmd.AddCommand(providersAddCmd)
+	providersCmd.AddCommand(providersUpdateCmd)
+	providersCmd.AddCommand(providersDeleteCmd)
+	providersCmd.AddCommand(providersTestCmd)
+	providersListCmd.Flags().StringVar(&providerCategoryFilterFlag, "category", "", "only list the providers of this category (OAuth, Email, SMS, Storage, Captcha...)")
+	providersListCmd.Flags().StringVar(&providerTypeFilterFlag, "type", "", "only list the providers of this type, such as GitHub or Aliyun SMS")
+	providersListCmd.Flags().BoolVar(&providerShowSecretsFlag, "show-secrets", false, "show the client secrets")
+	providersGetCmd.Flags().BoolVar(&providerShowSecretsFlag, "show-secrets", false, "show the secrets")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateDisplayFlag, "display-name", "", "display name of the provider")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateSubTypeFlag, "sub-type", "", "sub-type of the provider")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateClientIdFlag, "client-id", "", "client id of the provider, or SMTP username of Email providers")
+	providersUpdateCmd.Flags().BoolVar(&providerUpdateClientSecretFlag, "client-secret", false, "prompt for a new client secret")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateHostFlag, "host", "", "host of the provider, such as the SMTP server")
+	providersUpdateCmd.Flags().IntVar(&providerUpdatePortFlag, "port", 0, "port of the provider")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateEndpointFlag, "endpoint", "", "endpoint of Storage providers")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateRegionFlag, "region", "", "region of the provider")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateSignNameFlag, "sign-name", "", "sign name of SMS providers")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateTemplateFlag, "template-code", "", "template code of SMS providers")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateBucketFlag, "bucket", "", "bucket of Storage providers")
+	providersUpdateCmd.Flags().StringVar(&providerUpdateDomainFlag, "domain", "", "domain of Storage providers")
+	providersAddCmd.Flags().StringVar(&providerAddDisplayFlag, "display-name", "", "display name of the provider")
+	providersAddCmd.Flags().StringVar(&providerCategoryFlag, "category", "", "category of the provider (OAuth, Email, SMS, Storage, SAML, Payment, Captcha, Web3 or Notification)")
+	providersAddCmd.MarkFlagRequired("category")
+	providersAddCmd.Flags().StringVar(&providerTypeFlag, "type", "", "type of the provider, such as Default for SMTP, GitHub or Aliyun SMS")
+	providersAddCmd.MarkFlagRequired("type")
+	providersAddCmd.Flags().StringVar(&providerAddSubTypeFlag, "sub-type", "", "sub-type of the provider")
+	providersAddCmd.Flags().StringVar(&providerAddClientIdFlag, "client-id", "", "client id of the provider, or SMTP username of Email providers")
+	providersAddCmd.Flags().BoolVar(&providerAddClientSecretFlag, "client-secret", false, "prompt for the client secret")
+	providersAddCmd.Flags().StringVar(&providerAddHostFlag, "host", "", "host of the provider, such as the SMTP server")
+	providersAddCmd.Flags().IntVar(&providerAddPortFlag, "port", 0, "port of the provider")
+	providersAddCmd.Flags().StringVar(&providerAddEndpointFlag, "endpoint", "", "endpoint of Storage providers")
+	providersAddCmd.Flags().StringVar(&providerAddRegionFlag, "region", "", "region of the provider")
+	providersAddCmd.Flags().StringVar(&providerAddSignNameFlag, "sign-name", "", "sign name of SMS providers")
+	providersAddCmd.Flags().StringVar(&providerAddTemplateFlag, "template-code", "", "template code of SMS providers")
+	providersAddCmd.Flags().StringVar(&providerAddBucketFlag, "bucket", "", "bucket of Storage providers")
+	providersAddCmd.Flags().StringVar(&providerAddDomainFlag, "domain", "", "domain of Storage providers")
+	providersDeleteCmd.Flags().BoolVar(&providerForceFlag, "force", false, "delete the provider even if applications use it")
+}
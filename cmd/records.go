@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	recordUserFlag          string
+	recordActionFlag        string
+	recordIpFlag            string
+	recordPathFlag          string
+	recordSinceFlag         string
+	recordUntilFlag         string
+	recordLimitFlag         int
+	recordLinesFlag         int
+	recordIntervalFlag      time.Duration
+	recordExportFormatFlag  string
+	recordForwardFormatFlag string
+	recordCheckpointFlag    string
+	recordFileFlag          string
+	recordToFlag            string
+)
+
+var recordsCmd = &cobra.Command{
+	Use:   "records",
+	Short: "Search Casdoor audit records",
+	Long:  "Search Casdoor audit records, which Casdoor keeps for every API action, such as the deletion of a user",
+}
+
+var recordsSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "search Casdoor audit records",
+	Long: `search the Casdoor audit records of the organization, newest first. Records can be filtered by
+--user, --action (such as delete-user), --ip, --path (part of the request URI), and a time range
+given by --since and --until, either as a duration before now such as 24h, as an RFC 3339 time or as
+a date:
+
+casdoor records search --action delete-user --since 168h`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		filter, err := recordFilter()
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		records, err := userManager.SearchRecords(filter, recordLimitFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(records)
+	},
+}
+
+var recordsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "follow Casdoor audit records",
+	Long: `print the last Casdoor audit records of the organization, and then poll for new ones and print
+them as they come, like tail -f, until interrupted with Ctrl+C. Records are printed one per line,
+and as one JSON object per line with --output json. The filters are the ones of records search, besides --until.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		filter, err := recordFilter()
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.TailRecords(filter, recordLinesFlag, recordIntervalFlag, func(record map[string]interface{}) {
+			utils.PrintLine(record, helpers.RecordColumns)
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var recordsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export Casdoor audit records for a SIEM",
+	Long: `export the Casdoor audit records of a time range, oldest first, as one JSON object per line
+(--format ndjson), in the Common Event Format (cef) or in the Log Event Extended Format (leef), to a
+file or to the standard output without --file. The filters are the ones of records search.
+
+With --checkpoint, the last exported record is stored under the given name, and the next export with
+the same checkpoint only holds the newer records, so that exports can be scheduled:
+
+casdoor records export --format cef --checkpoint siem -f casdoor-$(date +%F).cef`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		filter, err := recordFilter()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var cursors *helpers.RecordCursors
+		checkpoint := fmt.Sprintf("%s/export/%s", config.OrganizationName, recordCheckpointFlag)
+		afterId := 0
+		if recordCheckpointFlag != "" {
+			cursors, err = loadRecordCursors()
+			if err != nil {
+				log.Fatal(err)
+			}
+			afterId, _ = cursors.LastId(checkpoint)
+		}
+
+		userManager := helpers.NewUserManager(config)
+		exported, lastId, count, err := userManager.ExportRecords(recordExportFormatFlag, filter, afterId)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if recordFileFlag == "" {
+			fmt.Print(exported)
+		} else if err = os.WriteFile(recordFileFlag, []byte(exported), 0600); err != nil {
+			log.Fatal(err)
+		}
+		// the checkpoint only moves once the records are written, so that none is lost
+		if recordCheckpointFlag != "" && count > 0 {
+			if err = cursors.Save(checkpoint, lastId); err != nil {
+				log.Fatal(err)
+			}
+		}
+		utils.Colorize(color.GreenString, "[✔] %d records exported", count)
+	},
+}
+
+var recordsForwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "forward Casdoor audit records to a SIEM",
+	Long: `forward the new Casdoor audit records of the organization as they come, until interrupted, to a
+syslog server over UDP (--to syslog://host:514), to a TCP or UDP listener receiving one record per
+line (tcp://host:port, udp://host:port), or to a file (file:///var/log/casdoor.cef).
+
+The last forwarded record is stored for each destination, so that a restarted forwarder resumes
+where it stopped. The first run starts with the records created from now on, or from --since.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		since, err := helpers.ParseRecordTime(recordSinceFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sink, err := helpers.NewRecordSink(recordToFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer sink.Close()
+		cursors, err := loadRecordCursors()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		userManager := helpers.NewUserManager(config)
+		cursor := fmt.Sprintf("%s/forward/%s", config.OrganizationName, recordToFlag)
+		err = userManager.ForwardRecords(sink, recordForwardFormatFlag, cursors, cursor, since, recordIntervalFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func loadRecordCursors() (*helpers.RecordCursors, error) {
+	casdoorFolder, _, err := getCasdoorFolderAndConfig()
+	if err != nil {
+		return nil, err
+	}
+	return helpers.LoadRecordCursors(filepath.Join(casdoorFolder, "record-cursors.json"))
+}
+
+func recordFilter() (helpers.RecordFilter, error) {
+	since, err := helpers.ParseRecordTime(recordSinceFlag)
+	if err != nil {
+		return helpers.RecordFilter{}, err
+	}
+	until, err := helpers.ParseRecordTime(recordUntilFlag)
+	if err != nil {
+		return helpers.RecordFilter{}, err
+	}
+	return helpers.RecordFilter{
+		User:     recordUserFlag,
+		Action:   recordActionFlag,
+		ClientIp: recordIpFlag,
+		Path:     recordPathFlag,
+		Since:    since,
+		Until:    until,
+	}, nil
+}
+
+func init() {
+	RootCmd.AddCommand(recordsCmd)
+	recordsCmd.AddCommand(recordsSearchCmd)
+	recordsCmd.AddCommand(recordsTailCmd)
+	recordsCmd.AddCommand(recordsExportCmd)
+	recordsCmd.AddCommand(recordsForwardCmd)
+	recordsSearchCmd.Flags().StringVar(&recordUserFlag, "user", "", "only the records of this user")
+	recordsSearchCmd.Flags().StringVar(&recordActionFlag, "action", "", "only the records of this action, such as login or delete-user")
+	recordsSearchCmd.Flags().StringVar(&recordIpFlag, "ip", "", "only the records from this client IP")
+	recordsSearchCmd.Flags().StringVar(&recordPathFlag, "path", "", "only the records whose request URI contains this path")
+	recordsSearchCmd.Flags().StringVar(&recordSinceFlag, "since", "", "only the records after this time, such as 24h, 2024-01-31 or 2024-01-31T12:00:00Z")
+	recordsSearchCmd.Flags().StringVar(&recordUntilFlag, "until", "", "only the records before this time, such as 1h, 2024-01-31 or 2024-01-31T12:00:00Z")
+	recordsSearchCmd.Flags().IntVar(&recordLimitFlag, "limit", 100, "maximum number of records to return")
+	recordsTailCmd.Flags().StringVar(&recordUserFlag, "user", "", "only the records of this user")
+	recordsTailCmd.Flags().StringVar(&recordActionFlag, "action", "", "only the records of this action, such as login or delete-user")
+	recordsTailCmd.Flags().StringVar(&recordIpFlag, "ip", "", "only the records from this client IP")
+	recordsTailCmd.Flags().StringVar(&recordPathFlag, "path", "", "only the records whose request URI contains this path")
+	recordsTailCmd.Flags().StringVar(&recordSinceFlag, "since", "", "only the records after this time, such as 24h, 2024-01-31 or 2024-01-31T12:00:00Z")
+	recordsTailCmd.Flags().IntVarP(&recordLinesFlag, "lines", "n", 10, "number of past records to print first")
+	recordsTailCmd.Flags().DurationVar(&recordIntervalFlag, "interval", 5*time.Second, "time between two polls")
+	recordsExportCmd.Flags().StringVar(&recordUserFlag, "user", "", "only the records of this user")
+	recordsExportCmd.Flags().StringVar(&recordActionFlag, "action", "", "only the records of this action, such as login or delete-user")
+	recordsExportCmd.Flags().StringVar(&recordIpFlag, "ip", "", "only the records from this client IP")
+	recordsExportCmd.Flags().StringVar(&recordPathFlag, "path", "", "only the records whose request URI contains this path")
+	recordsExportCmd.Flags().StringVar(&recordSinceFlag, "since", "", "only the records after this time, such as 24h, 2024-01-31 or 2024-01-31T12:00:00Z")
+	recordsExportCmd.Flags().StringVar(&recordUntilFlag, "until", "", "only the records before this time, such as 1h, 2024-01-31 or 2024-01-31T12:00:00Z")
+	recordsExportCmd.Flags().StringVar(&recordExportFormatFlag, "format", "ndjson", "format of the records (ndjson, cef or leef)")
+	recordsExportCmd.Flags().StringVar(&recordCheckpointFlag, "checkpoint", "", "name of the checkpoint storing the last exported record")
+	recordsExportCmd.Flags().StringVarP(&recordFileFlag, "file", "f", "", "file to export the records to")
+	recordsForwardCmd.Flags().StringVar(&recordToFlag, "to", "", "destination of the records (syslog://host:514, tcp://host:port, udp://host:port or file:///path)")
+	recordsForwardCmd.MarkFlagRequired("to")
+	recordsForwardCmd.Flags().StringVar(&recordForwardFormatFlag, "format", "cef", "format of the records (ndjson, cef or leef)")
+	recordsForwardCmd.Flags().StringVar(&recordSinceFlag, "since", "", "on the first run, forward the records after this time rather than the new ones only")
+	recordsForwardCmd.Flags().DurationVar(&recordIntervalFlag, "interval", 5*time.Second, "time between two polls")
+}
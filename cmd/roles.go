@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	roleUsersFlag             []string
+	roleSubRolesFlag          []string
+	roleAddDisplayFlag        string
+	roleAddDescriptionFlag    string
+	roleAddDomainsFlag        []string
+	roleAddEnabledFlag        bool
+	roleUpdateDisplayFlag     string
+	roleUpdateDescriptionFlag string
+	roleUpdateDomainsFlag     []string
+	roleUpdateEnabledFlag     bool
+)
+
+var rolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Manage Casdoor roles",
+	Long: `Manage Casdoor roles. Unlike groups, roles are Casdoor role objects holding users, sub-roles
+and domains, and are the subjects used by Casdoor permissions. The members of a sub-role inherit
+the role.`,
+}
+
+var rolesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor roles",
+	Long:  "list Casdoor roles",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		roles, err := userManager.GetRoles()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(roles)
+	},
+}
+
+var rolesGetCmd = &cobra.Command{
+	Use:   "get <role>",
+	Short: "get a Casdoor role",
+	Long:  "get a Casdoor role, along with its effective users which include the users of its sub-roles",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		role, err := userManager.GetRole(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(role)
+	},
+}
+
+var rolesAddCmd = &cobra.Command{
+	Use:   "add <role>",
+	Short: "add a Casdoor role",
+	Long:  "add a Casdoor role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddRole(args[0], helpers.RoleFields{
+			DisplayName: roleAddDisplayFlag,
+			Description: roleAddDescriptionFlag,
+			Users:       roleUsersFlag,
+			Roles:       roleSubRolesFlag,
+			Domains:     roleAddDomainsFlag,
+			Enabled:     roleAddEnabledFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var rolesUpdateCmd = &cobra.Command{
+	Use:   "update <role>",
+	Short: "update a Casdoor role",
+	Long:  "update a Casdoor role. Only the given fields are changed, and --domain replaces all the domains of the role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.RoleUpdate
+		if cmd.Flags().Changed("display-name") {
+			update.DisplayName = &roleUpdateDisplayFlag
+		}
+		if cmd.Flags().Changed("description") {
+			update.Description = &roleUpdateDescriptionFlag
+		}
+		if cmd.Flags().Changed("domain") {
+			update.Domains = &roleUpdateDomainsFlag
+		}
+		if cmd.Flags().Changed("enabled") {
+			update.Enabled = &roleUpdateEnabledFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateRole(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var rolesDeleteCmd = &cobra.Command{
+	Use:   "delete <role>",
+	Short: "delete a Casdoor role",
+	Long:  "delete a Casdoor role. It is also removed from the roles using it as a sub-role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the role %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteRole(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var rolesMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Manage the users and sub-roles of a Casdoor role",
+	Long:  "Manage the users and sub-roles of a Casdoor role",
+}
+
+var rolesMembersAddCmd = &cobra.Command{
+	Use:   "add <role>",
+	Short: "add users and sub-roles to a Casdoor role",
+	Long:  "add users (--user) and sub-roles (--role) to a Casdoor role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(roleUsersFlag) == 0 && len(roleSubRolesFlag) == 0 {
+			utils.Colorize(color.RedString, "[x] at least one --user or --role is required")
+			return
+		}
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddRoleMembers(args[0], roleUsersFlag, roleSubRolesFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var rolesMembersRemoveCmd = &cobra.Command{
+	Use:   "remove <role>",
+	Short: "remove users and sub-roles from a Casdoor role",
+	Long:  "remove users (--user) and sub-roles (--role) from a Casdoor role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(roleUsersFlag) == 0 && len(roleSubRolesFlag) == 0 {
+			utils.Colorize(color.RedString, "[x] at least one --user or --role is required")
+			return
+		}
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.RemoveRoleMembers(args[0], roleUsersFlag, roleSubRolesFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var rolesTreeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "show the Casdoor role inheritance",
+	Long:  "show the Casdoor role inheritance of the organization, with the number of direct and effective users of each role",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		tree, err := userManager.GetRoleTree()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(tree)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(rolesCmd)
+	rolesCmd.AddCommand(rolesListCmd)
+	rolesCmd.AddCommand(rolesGetCmd)
+	rolesCmd.AddCommand(rolesAddCmd)
+	rolesCmd.AddCommand(rolesUpdateCmd)
+	rolesCmd.AddCommand(rolesDeleteCmd)
+	rolesCmd.AddCommand(rolesTreeCmd)
+	rolesCmd.AddCommand(rolesMembersCmd)
+	rolesMembersCmd.AddCommand(rolesMembersAddCmd)
+	rolesMembersCmd.AddCommand(rolesMembersRemoveCmd)
+	rolesAddCmd.Flags().StringVar(&roleAddDisplayFlag, "display-name", "", "display name of the role")
+	rolesAddCmd.Flags().StringVar(&roleAddDescriptionFlag, "description", "", "description of the role")
+	rolesAddCmd.Flags().StringArrayVar(&roleAddDomainsFlag, "domain", nil, "domain of the role (repeatable)")
+	rolesAddCmd.Flags().BoolVar(&roleAddEnabledFlag, "enabled", true, "whether the role is enabled")
+	rolesUpdateCmd.Flags().StringVar(&roleUpdateDisplayFlag, "display-name", "", "display name of the role")
+	rolesUpdateCmd.Flags().StringVar(&roleUpdateDescriptionFlag, "description", "", "description of the role")
+	rolesUpdateCmd.Flags().StringArrayVar(&roleUpdateDomainsFlag, "domain", nil, "domain of the role (repeatable)")
+	rolesUpdateCmd.Flags().BoolVar(&roleUpdateEnabledFlag, "enabled", true, "whether the role is enabled")
+	rolesAddCmd.Flags().StringArrayVar(&roleUsersFlag, "user", nil, "user of the role (repeatable)")
+	rolesAddCmd.Flags().StringArrayVar(&roleSubRolesFlag, "role", nil, "sub-role of the role (repeatable)")
+	rolesMembersAddCmd.Flags().StringArrayVar(&roleUsersFlag, "user", nil, "user of the role (repeatable)")
+	rolesMembersAddCmd.Flags().StringArrayVar(&roleSubRolesFlag, "role", nil, "sub-role of the role (repeatable)")
+	rolesMembersRemoveCmd.Flags().StringArrayVar(&roleUsersFlag, "user", nil, "user of the role (repeatable)")
+	rolesMembersRemoveCmd.Flags().StringArrayVar(&roleSubRolesFlag, "role", nil, "sub-role of the role (repeatable)")
+}
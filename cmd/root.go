@@ -32,7 +32,19 @@ API such as:
 `),
 }
 
-var debug bool
+var (
+	debug   bool
+	orgFlag string
+)
+
+// optionalConfigKeys are the settings of the config which aren't base64-encoded, unlike the ones
+// written at initialization.
+var optionalConfigKeys = []string{
+	"authorization_mode",
+	"authorization_permission_id",
+	"authorization_model_id",
+	"policy_file",
+}
 
 func Execute() {
 	err := RootCmd.Execute()
@@ -44,6 +56,8 @@ func Execute() {
 func init() {
 	RootCmd.PersistentPreRun = rootPreRun
 	RootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "verbose logging")
+	RootCmd.PersistentFlags().StringVar(&orgFlag, "org", "", "organization to manage (defaults to organization_name from the config)")
+	RootCmd.PersistentFlags().StringVarP(&utils.OutputFormat, "output", "o", "table", "output format (table, json or yaml)")
 
 }
 
@@ -52,6 +66,9 @@ func init() {
 // the path to their config.yaml file and creates a new configuration.
 func rootPreRun(*cobra.Command, []string) {
 	logger.ToggleDebug(debug)
+	if err := utils.CheckOutputFormat(); err != nil {
+		log.Fatal(err)
+	}
 	folderExist, fileExists := checkCasdoorConfig()
 
 	if folderExist || fileExists {
@@ -134,7 +151,14 @@ func initCasdoorConfig() (*models.CasdoorConfig, error) {
 
 	decodedConfig := make(map[string]string)
 	for key, encodedValue := range viper.AllSettings() {
-		decodedBytes, err := base64.StdEncoding.DecodeString(encodedValue.(string))
+		if containsCommand(optionalConfigKeys, key) {
+			continue
+		}
+		encodedString, ok := encodedValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("error decoding base64 for %s: expected a string", key)
+		}
+		decodedBytes, err := base64.StdEncoding.DecodeString(encodedString)
 		if err != nil {
 			return nil, fmt.Errorf("error decoding base64 for %s: %v", key, err)
 		}
@@ -167,6 +191,12 @@ func initCasdoorConfig() (*models.CasdoorConfig, error) {
 		}
 	}
 
+	// optional settings are written by hand, so they are read as plain values
+	casdoorConfig.AuthorizationMode = viper.GetString("authorization_mode")
+	casdoorConfig.PermissionID = viper.GetString("authorization_permission_id")
+	casdoorConfig.ModelID = viper.GetString("authorization_model_id")
+	casdoorConfig.PolicyFile = viper.GetString("policy_file")
+
 	return casdoorConfig, err
 }
 
@@ -214,7 +244,7 @@ func createConfigFile(casdoorFolder string) error {
 
 	encodedConfig := make(map[string]string)
 	for key, value := range viper.AllSettings() {
-		encodedValue := base64.StdEncoding.EncodeToString([]byte(value.(string)))
+		encodedValue := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%v", value)))
 		encodedConfig[key] = encodedValue
 	}
 
@@ -229,3 +259,9 @@ func createConfigFile(casdoorFolder string) error {
 	}
 	return nil
 }
+
+// updateConfigValue base64-encodes a value and writes it to the config file loaded by initCasdoorConfig.
+func updateConfigValue(key string, value string) error {
+	viper.Set(key, base64.StdEncoding.EncodeToString([]byte(value)))
+	return viper.WriteConfig()
+}
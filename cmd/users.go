@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"gitlab.com/sdv9972401/casdoor-cli/helpers"
@@ -13,7 +14,15 @@ import (
 )
 
 var (
-	nameFlag string
+	nameFlag          string
+	exportFlag        string
+	generateFlag      bool
+	lengthFlag        int
+	showOnceFlag      bool
+	allOrgsFlag       bool
+	propertyFlag      []string
+	fileFlag          string
+	passwordsFileFlag string
 )
 
 var usersCmd = &cobra.Command{
@@ -27,18 +36,30 @@ var usersListCmd = &cobra.Command{
 	Short: "list Casdoor users",
 	Long:  "list Casdoor users",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-			"editor",
-			"lector",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
 
+		if err = checkAllOrgs(allOrgsFlag); err != nil {
+			return
+		}
+
+		properties, err := helpers.ParseProperties(propertyFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		userManager := helpers.NewUserManager(config)
-		users, err := userManager.GetUsers()
+		var users []map[string]interface{}
+		if allOrgsFlag {
+			users, err = userManager.GetGlobalUsers(properties)
+		} else {
+			users, err = userManager.GetUsers(properties)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
 		utils.PrintTables(users)
 	},
 }
@@ -48,11 +69,7 @@ var usersAddCmd = &cobra.Command{
 	Short: "add Casdoor user",
 	Long:  "add Casdoor user",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-			"editor",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
@@ -70,10 +87,7 @@ var usersDeleteCmd = &cobra.Command{
 	Short: "delete Casdoor user",
 	Long:  "delete Casdoor user",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
@@ -104,10 +118,7 @@ var userUpdateCmd = &cobra.Command{
 	Short: "update Casdoor user",
 	Long:  "update Casdoor user",
 	Run: func(cmd *cobra.Command, args []string) {
-		targetRoles := []string{
-			"administrator",
-		}
-		config, err := checkLoggedInAndGetConfig(targetRoles)
+		config, err := checkLoggedInAndGetConfig(cmd)
 		if err != nil {
 			return
 		}
@@ -119,11 +130,215 @@ var userUpdateCmd = &cobra.Command{
 	},
 }
 
-// checkLoggedInAndGetConfig checks if the user is logged in and has the required roles
-// to perform an action by using the access token data. If the user is not logged in or
-// doesn't have the required roles, an error will be returned. Otherwise, the Casdoor
-// configuration is returned.
-func checkLoggedInAndGetConfig(requiredRoles []string) (*models.CasdoorConfig, error) {
+var usersDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "disable Casdoor user",
+	Long:  "disable Casdoor user. The account is locked immediately but is not deleted",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetUserForbidden(args[0], true)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "enable Casdoor user",
+	Long:  "enable a previously disabled Casdoor user",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.SetUserForbidden(args[0], false)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersOffboardCmd = &cobra.Command{
+	Use:   "offboard <name>",
+	Short: "offboard Casdoor user",
+	Long: `offboard Casdoor user. In one go, this disables the account, revokes its tokens and sessions,
+removes its group memberships and optionally exports its profile to a file. The account itself is kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		if !userConfirms("[⚠] This will disable %v, revoke its tokens and sessions and remove its group memberships. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+
+		utils.Colorize(color.CyanString, "[ℹ] attempting to offboard user %v", args[0])
+		userManager := helpers.NewUserManager(config)
+		report, err := userManager.OffboardUser(args[0], exportFlag)
+		if report != nil {
+			utils.PrintTable(report)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersSetPasswordCmd = &cobra.Command{
+	Use:   "set-password <name>",
+	Short: "set the password of a Casdoor user",
+	Long: `set the password of a Casdoor user. The password is prompted for, or generated with --generate.
+Both must satisfy the password options of the organization.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+
+		var password string
+		if generateFlag {
+			options, err := userManager.GetPasswordOptions()
+			if err != nil {
+				log.Fatal(err)
+			}
+			password, err = helpers.GeneratePassword(options, lengthFlag)
+			if err != nil {
+				log.Fatal(err)
+			}
+		} else {
+			password, err = userManager.PromptUserPassword()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		err = userManager.SetUserPassword(args[0], password)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if generateFlag {
+			if showOnceFlag {
+				utils.ShowOnce("generated password", password)
+			} else {
+				fmt.Printf("generated password: %s\n", password)
+			}
+		}
+	},
+}
+
+var usersCheckPasswordCmd = &cobra.Command{
+	Use:   "check-password <name>",
+	Short: "check the password of a Casdoor user",
+	Long: `check whether the password given by a Casdoor user is the right one. The check goes through
+the sign-in password check of Casdoor, so a wrong password counts as a failed sign-in of the user,
+and enough of them lock the user out for a while, as set by the organization.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		passwordPrompt := promptui.Prompt{
+			Label: "Password",
+			Mask:  '*',
+		}
+		password, err := passwordPrompt.Run()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		userManager := helpers.NewUserManager(config)
+
+		valid, err := userManager.CheckUserPassword(args[0], password)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if valid {
+			utils.Colorize(color.GreenString, "[✔] password of %v is valid", args[0])
+		} else {
+			utils.Colorize(color.RedString, "[x] password of %v is invalid", args[0])
+		}
+	},
+}
+
+var usersExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export Casdoor users",
+	Long:  "export Casdoor users, including their groups and custom properties, to a JSON file",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.ExportUsers(fileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var usersImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "import Casdoor users",
+	Long: `import Casdoor users from a JSON file written by users export. Missing users are created,
+existing users get their display name, email, phone, groups, status and custom properties updated.
+
+New users get the password of their "password" field in the file. Without one, a password satisfying
+the password options of the organization is generated and appended to --passwords-file as soon as
+the user is created, so the file keeps the passwords of the users created before a failure. Nothing is
+imported when a new user has no password and no --passwords-file is given, or when a group doesn't exist.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.ImportUsers(fileFlag, passwordsFileFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// userConfirms prints the given question and returns true if the user answers "y" or "yes".
+func userConfirms(format string, a ...interface{}) bool {
+	fmt.Print(color.YellowString(format, a...))
+
+	var userResponse string
+	_, err := fmt.Scanln(&userResponse)
+	if err != nil {
+		return false
+	}
+
+	return strings.ToLower(userResponse) == "y" || strings.ToLower(userResponse) == "yes"
+}
+
+// checkLoggedInAndGetConfig checks if the user is logged in and is allowed to run the command,
+// according to the authorization mode of the config: in "local" mode the policy must allow one of
+// the user's groups to run the command, in "casdoor" mode Casdoor must allow the action of the
+// command. If the user is not logged in or isn't allowed, an error will be returned. Otherwise,
+// the Casdoor configuration is returned, targeting the organization given by --org if any.
+func checkLoggedInAndGetConfig(cmd *cobra.Command) (*models.CasdoorConfig, error) {
+	return checkLoggedInAndGetConfigForPath(commandPath(cmd))
+}
+
+// checkLoggedInAndGetConfigForPath checks that the logged-in user may run the given command path.
+func checkLoggedInAndGetConfigForPath(path string) (*models.CasdoorConfig, error) {
 	config, err := initCasdoorConfig()
 	if err != nil {
 		log.Fatal(err)
@@ -134,22 +349,72 @@ func checkLoggedInAndGetConfig(requiredRoles []string) (*models.CasdoorConfig, e
 		return nil, err
 	}
 
-	if !helpers.HasRequiredGroup(existingTokenData.IDTokenClaims.Groups, requiredRoles) {
-		utils.Colorize(color.RedString, "[x] you don't have enough permissions to perform this action (required roles: %v)", requiredRoles)
-		return nil, errors.New("insufficient permissions")
+	if err = checkAuthorized(config, existingTokenData, path); err != nil {
+		return nil, err
+	}
+
+	// the groups of the user are the ones of its own organization, so managing another one is
+	// authorized on its own
+	if orgFlag != "" && orgFlag != config.OrganizationName {
+		if err = checkAuthorized(config, existingTokenData, otherOrgsPath); err != nil {
+			return nil, err
+		}
+		config.OrganizationName = orgFlag
 	}
 
 	return config, nil
 }
 
+// checkAuthorized returns an error, after telling the user why, unless they may run the command path.
+func checkAuthorized(config *models.CasdoorConfig, tokenData *models.TokenData, path string) error {
+	decision, err := authorize(config, tokenData, path)
+	if err != nil {
+		utils.Colorize(color.RedString, "[x] %v", err)
+		return err
+	}
+	if !decision.Allowed {
+		utils.Colorize(color.RedString, "[x] you don't have enough permissions to perform this action (%v: %v)", decision.Source, decision.Rule)
+		return errors.New("insufficient permissions")
+	}
+	log.Debugf("%v allowed by %v: %v", path, decision.Source, decision.Rule)
+	return nil
+}
+
+// checkAllOrgs authorizes --all-orgs the way --org is, as it reaches the other organizations
+func checkAllOrgs(allOrgs bool) error {
+	if !allOrgs {
+		return nil
+	}
+	_, err := checkLoggedInAndGetConfigForPath(otherOrgsPath)
+	return err
+}
+
 func init() {
 	RootCmd.AddCommand(usersCmd)
 	usersCmd.AddCommand(usersListCmd)
+	usersListCmd.Flags().BoolVar(&allOrgsFlag, "all-orgs", false, "list the users of every organization")
+	usersListCmd.Flags().StringArrayVarP(&propertyFlag, "property", "p", nil, "only list users having this key=value property (repeatable)")
 	usersCmd.AddCommand(usersAddCmd)
 	usersCmd.AddCommand(usersDeleteCmd)
 	usersCmd.AddCommand(userUpdateCmd)
+	usersCmd.AddCommand(usersDisableCmd)
+	usersCmd.AddCommand(usersEnableCmd)
+	usersCmd.AddCommand(usersOffboardCmd)
+	usersCmd.AddCommand(usersSetPasswordCmd)
+	usersCmd.AddCommand(usersCheckPasswordCmd)
+	usersCmd.AddCommand(usersExportCmd)
+	usersCmd.AddCommand(usersImportCmd)
 	usersDeleteCmd.Flags().StringVarP(&nameFlag, "name", "n", "", "name of the user")
 	usersDeleteCmd.MarkFlagRequired("name")
 	userUpdateCmd.Flags().StringVarP(&nameFlag, "name", "n", "", "name of the user")
 	userUpdateCmd.MarkFlagRequired("name")
+	usersOffboardCmd.Flags().StringVarP(&exportFlag, "export", "e", "", "export the user profile to this file before offboarding")
+	usersSetPasswordCmd.Flags().BoolVarP(&generateFlag, "generate", "g", false, "generate a password satisfying the organization password options")
+	usersSetPasswordCmd.Flags().IntVarP(&lengthFlag, "length", "l", 16, "length of the generated password")
+	usersSetPasswordCmd.Flags().BoolVar(&showOnceFlag, "show-once", false, "erase the generated password from the terminal once saved")
+	usersExportCmd.Flags().StringVarP(&fileFlag, "file", "f", "", "path of the JSON file")
+	usersExportCmd.MarkFlagRequired("file")
+	usersImportCmd.Flags().StringVarP(&fileFlag, "file", "f", "", "path of the JSON file")
+	usersImportCmd.MarkFlagRequired("file")
+	usersImportCmd.Flags().StringVar(&passwordsFileFlag, "passwords-file", "", "file to write the passwords generated for the new users to")
 }
@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gitlab.com/sdv9972401/casdoor-cli/helpers"
+	"gitlab.com/sdv9972401/casdoor-cli/logger"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+)
+
+var (
+	webhookListenHeaderFlag       []string
+	webhookBindFlag               string
+	webhookPortFlag               int
+	webhookExecFlag               string
+	webhookAddUrlFlag             string
+	webhookAddMethodFlag          string
+	webhookAddContentTypeFlag     string
+	webhookAddHeaderFlag          []string
+	webhookAddEventFlag           []string
+	webhookAddUserExtendedFlag    bool
+	webhookAddEnabledFlag         bool
+	webhookUpdateUrlFlag          string
+	webhookUpdateMethodFlag       string
+	webhookUpdateContentTypeFlag  string
+	webhookUpdateHeaderFlag       []string
+	webhookUpdateEventFlag        []string
+	webhookUpdateUserExtendedFlag bool
+	webhookUpdateEnabledFlag      bool
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Manage Casdoor webhooks",
+	Long:  "Manage Casdoor webhooks, which deliver the events of the organization to a URL, and receive them locally",
+}
+
+var webhooksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list Casdoor webhooks",
+	Long:  "list the Casdoor webhooks of the organization",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		webhooks, err := userManager.GetWebhooks()
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTables(webhooks)
+	},
+}
+
+var webhooksGetCmd = &cobra.Command{
+	Use:   "get <webhook>",
+	Short: "get a Casdoor webhook",
+	Long:  "get a Casdoor webhook along with its headers and events",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		webhook, err := userManager.GetWebhook(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		utils.PrintTable(webhook)
+	},
+}
+
+var webhooksAddCmd = &cobra.Command{
+	Use:   "add <webhook>",
+	Short: "add a Casdoor webhook",
+	Long: `add a Casdoor webhook delivering the given events, such as --event signup --event delete-user, to
+a URL. Headers sent along with the events are given as --header key=value.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		headers, err := helpers.ParseProperties(webhookAddHeaderFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.AddWebhook(args[0], helpers.WebhookFields{
+			Url:            webhookAddUrlFlag,
+			Method:         webhookAddMethodFlag,
+			ContentType:    webhookAddContentTypeFlag,
+			Headers:        headers,
+			Events:         webhookAddEventFlag,
+			IsUserExtended: webhookAddUserExtendedFlag,
+			IsEnabled:      webhookAddEnabledFlag,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var webhooksUpdateCmd = &cobra.Command{
+	Use:   "update <webhook>",
+	Short: "update a Casdoor webhook",
+	Long: `update a Casdoor webhook. Only the given fields are changed, the given --event list replaces the
+current one, and --header key= removes a header.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+
+		var update helpers.WebhookUpdate
+		if cmd.Flags().Changed("url") {
+			update.Url = &webhookUpdateUrlFlag
+		}
+		if cmd.Flags().Changed("method") {
+			update.Method = &webhookUpdateMethodFlag
+		}
+		if cmd.Flags().Changed("content-type") {
+			update.ContentType = &webhookUpdateContentTypeFlag
+		}
+		update.Headers, err = helpers.ParseProperties(webhookUpdateHeaderFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if cmd.Flags().Changed("event") {
+			update.Events = &webhookUpdateEventFlag
+		}
+		if cmd.Flags().Changed("user-extended") {
+			update.IsUserExtended = &webhookUpdateUserExtendedFlag
+		}
+		if cmd.Flags().Changed("enabled") {
+			update.IsEnabled = &webhookUpdateEnabledFlag
+		}
+
+		userManager := helpers.NewUserManager(config)
+		err = userManager.UpdateWebhook(args[0], update)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var webhooksDeleteCmd = &cobra.Command{
+	Use:   "delete <webhook>",
+	Short: "delete a Casdoor webhook",
+	Long:  "delete a Casdoor webhook",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := checkLoggedInAndGetConfig(cmd)
+		if err != nil {
+			return
+		}
+		if !userConfirms("[⚠] This will delete the webhook %v. Are you sure about that ? [y/N]: ", args[0]) {
+			utils.Colorize(color.RedString, "[x] operation canceled")
+			return
+		}
+		userManager := helpers.NewUserManager(config)
+		err = userManager.DeleteWebhook(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var webhooksListenCmd = &cobra.Command{
+	Use:   "listen",
+	Short: "receive Casdoor webhook events locally",
+	Long: `receive Casdoor webhook events on a local port and pretty-print them, in order to develop an
+integration without deploying an endpoint. Point a webhook to the listener, for instance with
+casdoor webhooks add dev --url http://localhost:8080/events --event login
+
+The listener only accepts local connections, unless another address is given with --bind, such as
+--bind 0.0.0.0 for a Casdoor running in a container. With --header key=value, events without this
+header are rejected with status 401, the way the real endpoint would do. With --exec, the given
+script is run for each event, with the event on its standard input and its action and user in
+CASDOOR_EVENT_ACTION and CASDOOR_EVENT_USER. The script runs for one event at a time and is killed
+after a minute, and events are refused with status 503 while 4 others are waiting for it. Since
+anyone reaching the listener could trigger it, --exec requires --header.`,
+	// the listener doesn't talk to Casdoor, so the endpoint check of the root command is skipped
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		logger.ToggleDebug(debug)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		headers, err := helpers.ParseProperties(webhookListenHeaderFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+		listener := &helpers.WebhookListener{
+			Address: webhookBindFlag,
+			Port:    webhookPortFlag,
+			Headers: headers,
+			Script:  webhookExecFlag,
+		}
+		if err = listener.Listen(); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(webhooksCmd)
+	webhooksCmd.AddCommand(webhooksListCmd)
+	webhooksCmd.AddCommand(webhooksGetCmd)
+	webhooksCmd.AddCommand(webhooksAddCmd)
+	webhooksCmd.AddCommand(webhooksUpdateCmd)
+	webhooksCmd.AddCommand(webhooksDeleteCmd)
+	webhooksCmd.AddCommand(webhooksListenCmd)
+	webhooksUpdateCmd.Flags().StringVar(&webhookUpdateUrlFlag, "url", "", "URL the events are delivered to")
+	webhooksUpdateCmd.Flags().StringVar(&webhookUpdateMethodFlag, "method", "", "HTTP method of the deliveries (POST, GET, PUT or DELETE)")
+	webhooksUpdateCmd.Flags().StringVar(&webhookUpdateContentTypeFlag, "content-type", "", "content type of the deliveries (application/json or application/x-www-form-urlencoded)")
+	webhooksUpdateCmd.Flags().StringArrayVar(&webhookUpdateHeaderFlag, "header", nil, "key=value header sent with the deliveries, removed when the value is empty (repeatable)")
+	webhooksUpdateCmd.Flags().StringArrayVar(&webhookUpdateEventFlag, "event", nil, "event to deliver, such as signup, login or delete-user (repeatable)")
+	webhooksUpdateCmd.Flags().BoolVar(&webhookUpdateUserExtendedFlag, "user-extended", false, "whether the user the event is about is sent along with it")
+	webhooksUpdateCmd.Flags().BoolVar(&webhookUpdateEnabledFlag, "enabled", false, "whether the webhook is enabled")
+	webhooksAddCmd.Flags().StringVar(&webhookAddUrlFlag, "url", "", "URL the events are delivered to")
+	webhooksAddCmd.MarkFlagRequired("url")
+	webhooksAddCmd.Flags().StringVar(&webhookAddMethodFlag, "method", "POST", "HTTP method of the deliveries (POST, GET, PUT or DELETE)")
+	webhooksAddCmd.Flags().StringVar(&webhookAddContentTypeFlag, "content-type", "application/json", "content type of the deliveries (application/json or application/x-www-form-urlencoded)")
+	webhooksAddCmd.Flags().StringArrayVar(&webhookAddHeaderFlag, "header", nil, "key=value header sent with the deliveries (repeatable)")
+	webhooksAddCmd.Flags().StringArrayVar(&webhookAddEventFlag, "event", nil, "event to deliver, such as signup, login or delete-user (repeatable)")
+	webhooksAddCmd.Flags().BoolVar(&webhookAddUserExtendedFlag, "user-extended", false, "send the user the event is about along with it")
+	webhooksAddCmd.Flags().BoolVar(&webhookAddEnabledFlag, "enabled", true, "whether the webhook is enabled")
+	webhooksListenCmd.Flags().StringVar(&webhookBindFlag, "bind", "127.0.0.1", "address to listen on")
+	webhooksListenCmd.Flags().IntVar(&webhookPortFlag, "port", 8080, "port to listen on")
+	webhooksListenCmd.Flags().StringArrayVar(&webhookListenHeaderFlag, "header", nil, "key=value header the events must carry (repeatable)")
+	webhooksListenCmd.Flags().StringVar(&webhookExecFlag, "exec", "", "script to run for each event, with the event on its standard input")
+}
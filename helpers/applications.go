@@ -0,0 +1,451 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+	"time"
+)
+
+// Values accepted by Casdoor for the application settings.
+var (
+	GrantTypes   = []string{"authorization_code", "password", "client_credentials", "token", "id_token", "refresh_token", "urn:ietf:params:oauth:grant-type:device_code"}
+	TokenFormats = []string{"JWT", "JWT-Empty", "JWT-Custom", "JWT-Standard"}
+)
+
+// ApplicationFields holds the fields of a new application besides its name.
+type ApplicationFields struct {
+	DisplayName          string
+	Description          string
+	HomepageUrl          string
+	Cert                 string
+	RedirectUris         []string
+	GrantTypes           []string
+	TokenFormat          string
+	ExpireInHours        int
+	RefreshExpireInHours int
+}
+
+// ApplicationUpdate holds the fields to change on an application. Nil fields are preserved.
+type ApplicationUpdate struct {
+	DisplayName          *string
+	Description          *string
+	HomepageUrl          *string
+	Cert                 *string
+	TokenFormat          *string
+	ExpireInHours        *int
+	RefreshExpireInHours *int
+}
+
+// ApplicationProviderFields holds the settings of a provider within an application. Nil fields are
+// left unchanged on a provider the application already has, and are false or empty on a new one.
+type ApplicationProviderFields struct {
+	CanSignUp *bool
+	CanSignIn *bool
+	CanUnlink *bool
+	Prompted  *bool
+	Rule      *string
+}
+
+// GetApplications returns the applications of the organization, or every application with allOrgs,
+// along with the age of their client secret. Client secrets are masked unless showSecrets is set.
+func (um *UserManager) GetApplications(allOrgs bool, showSecrets bool, rotations *SecretRotations) ([]map[string]interface{}, error) {
+	var applications []*casdoorsdk.Application
+	var err error
+	if allOrgs {
+		applications, err = um.client.GetApplications()
+	} else {
+		applications, err = um.client.GetOrganizationApplications()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var applicationList []map[string]interface{}
+
+	for _, application := range applications {
+		applicationList = append(applicationList, map[string]interface{}{
+			"Name":         application.Name,
+			"Organization": application.Organization,
+			"ClientId":     application.ClientId,
+			"ClientSecret": shownSecret(application.ClientSecret, showSecrets),
+			"SecretAge":    rotations.Age(application.Name),
+			"GrantTypes":   strings.Join(application.GrantTypes, ", "),
+			"TokenFormat":  application.TokenFormat,
+		})
+	}
+	return applicationList, nil
+}
+
+// GetApplication returns the settings of an application. Its client secret is masked unless showSecrets is set.
+func (um *UserManager) GetApplication(name string, showSecrets bool) (map[string]interface{}, error) {
+	application, err := um.findApplication(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var providers []string
+	for _, provider := range application.Providers {
+		providers = append(providers, provider.Name)
+	}
+	return map[string]interface{}{
+		"Name":                 application.Name,
+		"DisplayName":          application.DisplayName,
+		"Organization":         application.Organization,
+		"Cert":                 application.Cert,
+		"ClientId":             application.ClientId,
+		"ClientSecret":         shownSecret(application.ClientSecret, showSecrets),
+		"RedirectUris":         strings.Join(application.RedirectUris, ", "),
+		"GrantTypes":           strings.Join(application.GrantTypes, ", "),
+		"TokenFormat":          application.TokenFormat,
+		"ExpireInHours":        application.ExpireInHours,
+		"RefreshExpireInHours": application.RefreshExpireInHours,
+		"Providers":            strings.Join(providers, ", "),
+	}, nil
+}
+
+// GetApplicationProviders returns the providers of an application along with their settings.
+func (um *UserManager) GetApplicationProviders(name string) ([]map[string]interface{}, error) {
+	application, err := um.findApplication(name)
+	if err != nil {
+		return nil, err
+	}
+	var providerList []map[string]interface{}
+
+	for _, provider := range application.Providers {
+		providerList = append(providerList, map[string]interface{}{
+			"Name":      provider.Name,
+			"CanSignUp": provider.CanSignUp,
+			"CanSignIn": provider.CanSignIn,
+			"CanUnlink": provider.CanUnlink,
+			"Prompted":  provider.Prompted,
+			"Rule":      provider.Rule,
+		})
+	}
+	return providerList, nil
+}
+
+// AddApplication creates an application in the organization. Casdoor generates its client id and secret.
+func (um *UserManager) AddApplication(name string, fields ApplicationFields) error {
+	existing, err := um.client.GetApplication(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("application %v already exists", name)
+	}
+	if err = checkGrantTypes(fields.GrantTypes); err != nil {
+		return err
+	}
+	if err = checkTokenFormat(fields.TokenFormat); err != nil {
+		return err
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	_, err = um.client.AddApplication(&casdoorsdk.Application{
+		Owner:                "admin",
+		Name:                 name,
+		CreatedTime:          time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName:          displayName,
+		Description:          fields.Description,
+		HomepageUrl:          fields.HomepageUrl,
+		Organization:         um.client.OrganizationName,
+		Cert:                 fields.Cert,
+		EnablePassword:       true,
+		Providers:            []*casdoorsdk.ProviderItem{},
+		SignupItems:          []*casdoorsdk.SignupItem{},
+		GrantTypes:           nonNilList(fields.GrantTypes),
+		Tags:                 []string{},
+		RedirectUris:         nonNilList(fields.RedirectUris),
+		TokenFormat:          fields.TokenFormat,
+		ExpireInHours:        fields.ExpireInHours,
+		RefreshExpireInHours: fields.RefreshExpireInHours,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v application has been created successfully", name)
+	return nil
+}
+
+// UpdateApplication changes the given settings of an application and preserves the other ones.
+func (um *UserManager) UpdateApplication(name string, update ApplicationUpdate) error {
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	setRaw := func(key string, value interface{}) {
+		raw[key] = value
+		changed = true
+	}
+	if update.DisplayName != nil {
+		setRaw("displayName", *update.DisplayName)
+	}
+	if update.Description != nil {
+		setRaw("description", *update.Description)
+	}
+	if update.HomepageUrl != nil {
+		setRaw("homepageUrl", *update.HomepageUrl)
+	}
+	if update.Cert != nil {
+		setRaw("cert", *update.Cert)
+	}
+	if update.TokenFormat != nil {
+		if err = checkTokenFormat(*update.TokenFormat); err != nil {
+			return err
+		}
+		setRaw("tokenFormat", *update.TokenFormat)
+	}
+	if update.ExpireInHours != nil {
+		if *update.ExpireInHours <= 0 {
+			return fmt.Errorf("expiry must be a positive number of hours")
+		}
+		setRaw("expireInHours", *update.ExpireInHours)
+	}
+	if update.RefreshExpireInHours != nil {
+		if *update.RefreshExpireInHours <= 0 {
+			return fmt.Errorf("refresh expiry must be a positive number of hours")
+		}
+		setRaw("refreshExpireInHours", *update.RefreshExpireInHours)
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update on application %v", name)
+	}
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v application has been updated successfully", name)
+	return nil
+}
+
+// DeleteApplication deletes an application. The application of the CLI itself cannot be deleted.
+func (um *UserManager) DeleteApplication(name string) error {
+	if name == um.client.ApplicationName {
+		return fmt.Errorf("application %v is the application of the CLI and cannot be deleted", name)
+	}
+	application, err := um.findApplication(name)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DeleteApplication(application)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v application has been deleted successfully", name)
+	return nil
+}
+
+// AddRedirectUris adds redirect URIs to an application, skipping the ones it already has.
+func (um *UserManager) AddRedirectUris(name string, uris []string) error {
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+	redirectUris := rawStrings(raw, "redirectUris")
+	for _, uri := range uris {
+		if containsString(redirectUris, uri) {
+			utils.Colorize(color.CyanString, "[ℹ] %v is already a redirect URI of %v", uri, name)
+			continue
+		}
+		redirectUris = append(redirectUris, uri)
+	}
+	raw["redirectUris"] = redirectUris
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] redirect URIs of %v have been updated successfully", name)
+	return nil
+}
+
+// RemoveRedirectUris removes redirect URIs from an application.
+func (um *UserManager) RemoveRedirectUris(name string, uris []string) error {
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+	redirectUris := rawStrings(raw, "redirectUris")
+	for _, uri := range uris {
+		if !containsString(redirectUris, uri) {
+			return fmt.Errorf("%v is not a redirect URI of %v", uri, name)
+		}
+	}
+	raw["redirectUris"] = removeStrings(redirectUris, uris)
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] redirect URIs of %v have been updated successfully", name)
+	return nil
+}
+
+// SetGrantTypes enables or disables grant types, such as client_credentials, on an application.
+func (um *UserManager) SetGrantTypes(name string, grantTypes []string, enabled bool) error {
+	if err := checkGrantTypes(grantTypes); err != nil {
+		return err
+	}
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+	current := rawStrings(raw, "grantTypes")
+	if enabled {
+		for _, grantType := range grantTypes {
+			if !containsString(current, grantType) {
+				current = append(current, grantType)
+			}
+		}
+	} else {
+		current = removeStrings(current, grantTypes)
+	}
+	raw["grantTypes"] = current
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] grant types of %v have been updated successfully", name)
+	return nil
+}
+
+// AddApplicationProvider adds a provider of the organization to an application, or changes its settings
+// if the application already has it.
+func (um *UserManager) AddApplicationProvider(name string, provider string, fields ApplicationProviderFields) error {
+	if _, _, err := um.findProvider(provider); err != nil {
+		return err
+	}
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+
+	settings := map[string]interface{}{}
+	if fields.CanSignUp != nil {
+		settings["canSignUp"] = *fields.CanSignUp
+	}
+	if fields.CanSignIn != nil {
+		settings["canSignIn"] = *fields.CanSignIn
+	}
+	if fields.CanUnlink != nil {
+		settings["canUnlink"] = *fields.CanUnlink
+	}
+	if fields.Prompted != nil {
+		settings["prompted"] = *fields.Prompted
+	}
+	if fields.Rule != nil {
+		settings["rule"] = *fields.Rule
+	}
+	providers, _ := raw["providers"].([]interface{})
+	found := false
+	for _, rawItem := range providers {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok || item["name"] != provider {
+			continue
+		}
+		found = true
+		for key, value := range settings {
+			item[key] = value
+		}
+	}
+	if !found {
+		item := map[string]interface{}{
+			"owner": "", "name": provider, "signupGroup": "", "provider": nil,
+			"canSignUp": false, "canSignIn": false, "canUnlink": false, "prompted": false, "rule": "",
+		}
+		for key, value := range settings {
+			item[key] = value
+		}
+		providers = append(providers, item)
+	}
+	raw["providers"] = providers
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v provider has been set on %v successfully", provider, name)
+	return nil
+}
+
+// RemoveApplicationProvider removes a provider from an application. The provider itself is kept.
+func (um *UserManager) RemoveApplicationProvider(name string, provider string) error {
+	raw, err := um.getRawApplication(name)
+	if err != nil {
+		return err
+	}
+
+	providers, _ := raw["providers"].([]interface{})
+	kept := []interface{}{}
+	for _, rawItem := range providers {
+		if item, ok := rawItem.(map[string]interface{}); ok && item["name"] == provider {
+			continue
+		}
+		kept = append(kept, rawItem)
+	}
+	if len(kept) == len(providers) {
+		return fmt.Errorf("%v is not a provider of %v", provider, name)
+	}
+	raw["providers"] = kept
+
+	err = um.updateRawApplication(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v provider has been removed from %v successfully", provider, name)
+	return nil
+}
+
+func (um *UserManager) findApplication(name string) (*casdoorsdk.Application, error) {
+	application, err := um.client.GetApplication(name)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, fmt.Errorf("application %v doesn't exist", name)
+	}
+	return application, nil
+}
+
+func (um *UserManager) getRawApplication(name string) (map[string]interface{}, error) {
+	if _, err := um.findApplication(name); err != nil {
+		return nil, err
+	}
+	return um.getRawObject("get-application", fmt.Sprintf("admin/%s", name))
+}
+
+func (um *UserManager) updateRawApplication(name string, raw map[string]interface{}) error {
+	return um.updateRawObject("update-application", fmt.Sprintf("admin/%s", name), raw)
+}
+
+func shownSecret(secret string, showSecrets bool) string {
+	if showSecrets {
+		return secret
+	}
+	return utils.MaskSecret(secret)
+}
+
+func checkGrantTypes(grantTypes []string) error {
+	for _, grantType := range grantTypes {
+		if !containsString(GrantTypes, grantType) {
+			return fmt.Errorf("invalid grant type %v (expected one of %v)", grantType, strings.Join(GrantTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func checkTokenFormat(tokenFormat string) error {
+	if !containsString(TokenFormats, tokenFormat) {
+		return fmt.Errorf("invalid token format %v (expected one of %v)", tokenFormat, strings.Join(TokenFormats, ", "))
+	}
+	return nil
+}
@@ -1,5 +1,9 @@
 package helpers
 
+import (
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+)
+
 // HasRequiredGroup checks if the user has at least one of the required permissions
 // in their claims. Returns true if the user has any of the target permissions;
 // otherwise, returns false.
@@ -13,3 +17,52 @@ func HasRequiredGroup(claimsPermission []string, targetPermission []string) bool
 	}
 	return false
 }
+
+// Enforce asks Casdoor whether the subject may perform the action on the object, according to
+// the given permission or model.
+func (um *UserManager) Enforce(permissionId string, modelId string, subject string, object string, action string) (bool, error) {
+	return um.client.Enforce(permissionId, modelId, "", []interface{}{subject, object, action})
+}
+
+// BatchEnforce asks Casdoor whether each of the subject, object, action requests is allowed.
+// A request is allowed if any of the enforcers of the permission or model allows it.
+func (um *UserManager) BatchEnforce(permissionId string, modelId string, requests [][]string) ([]bool, error) {
+	var casbinRequests []casdoorsdk.CasbinRequest
+	for _, request := range requests {
+		casbinRequest := casdoorsdk.CasbinRequest{}
+		for _, value := range request {
+			casbinRequest = append(casbinRequest, value)
+		}
+		casbinRequests = append(casbinRequests, casbinRequest)
+	}
+
+	results, err := um.client.BatchEnforce(permissionId, modelId, "", casbinRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]bool, len(requests))
+	for _, enforcerResults := range results {
+		for i, result := range enforcerResults {
+			if i < len(allowed) && result {
+				allowed[i] = true
+			}
+		}
+	}
+	return allowed, nil
+}
+
+// VerifyAccessToken checks the signature of an access token against the certificate of the
+// config, and returns its claims.
+func (um *UserManager) VerifyAccessToken(token string) (*casdoorsdk.Claims, error) {
+	return um.client.ParseJwtToken(token)
+}
+
+// GetUserGroups returns the names of the groups of the user.
+func (um *UserManager) GetUserGroups(name string) ([]string, error) {
+	user, err := um.findUser(name)
+	if err != nil {
+		return nil, err
+	}
+	return groupNames(user.Groups), nil
+}
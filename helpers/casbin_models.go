@@ -0,0 +1,244 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casbin/casbin/v2/constant"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/util"
+	"github.com/casbin/govaluate"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// supportedEffects are the policy effects Casbin knows how to evaluate.
+var supportedEffects = []string{
+	constant.AllowOverrideEffect,
+	constant.DenyOverrideEffect,
+	constant.AllowAndDenyEffect,
+	constant.PriorityEffect,
+	constant.SubjectPriorityEffect,
+}
+
+// GetModels returns the Casbin models of the organization.
+func (um *UserManager) GetModels() ([]map[string]interface{}, error) {
+	models, err := um.client.GetModels()
+	if err != nil {
+		return nil, err
+	}
+	var modelList []map[string]interface{}
+
+	for _, casbinModel := range models {
+		modelList = append(modelList, map[string]interface{}{
+			"Name":        casbinModel.Name,
+			"DisplayName": casbinModel.DisplayName,
+			"CreatedTime": casbinModel.CreatedTime,
+			"Matcher":     modelMatcher(casbinModel.ModelText),
+		})
+	}
+	return modelList, nil
+}
+
+// GetModelText returns the text of a Casbin model, as it would be written in a model.conf file.
+func (um *UserManager) GetModelText(name string) (string, error) {
+	casbinModel, err := um.findModel(name)
+	if err != nil {
+		return "", err
+	}
+	return normalizeModelText(casbinModel.ModelText), nil
+}
+
+// DiffModel compares the text of a Casbin model on the server with a local model file.
+func (um *UserManager) DiffModel(name string, path string) ([]string, error) {
+	text, err := readModelFile(path)
+	if err != nil {
+		return nil, err
+	}
+	serverText, err := um.GetModelText(name)
+	if err != nil {
+		return nil, err
+	}
+	return utils.DiffLines(serverText, text), nil
+}
+
+// ApplyModel validates a local model file and uploads it, creating the model if it doesn't exist
+// yet. With dryRun, the model is only validated and compared with the server copy.
+func (um *UserManager) ApplyModel(name string, path string, dryRun bool) error {
+	text, err := readModelFile(path)
+	if err != nil {
+		return err
+	}
+	if err = ValidateModelText(text); err != nil {
+		return fmt.Errorf("invalid model %v: %v", path, err)
+	}
+	utils.Colorize(color.CyanString, "[ℹ] %v is a valid model", path)
+
+	existing, err := um.client.GetModel(name)
+	if err != nil {
+		return err
+	}
+	serverText := ""
+	if existing != nil {
+		serverText = normalizeModelText(existing.ModelText)
+	}
+	diff := utils.DiffLines(serverText, text)
+	if !utils.HasChanges(diff) {
+		utils.Colorize(color.GreenString, "[✔] %v model is already up to date", name)
+		return nil
+	}
+	utils.PrintDiff(diff)
+	if dryRun {
+		return nil
+	}
+
+	if existing == nil {
+		_, err = um.client.AddModel(&casdoorsdk.Model{
+			Owner:       um.client.OrganizationName,
+			Name:        name,
+			CreatedTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			DisplayName: name,
+			ModelText:   text,
+			IsEnabled:   true,
+		})
+		if err != nil {
+			return err
+		}
+		utils.Colorize(color.GreenString, "[✔] %v model has been created successfully", name)
+		return nil
+	}
+
+	existing.ModelText = text
+	existing.UpdatedTime = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err = um.client.UpdateModel(existing)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v model has been updated successfully", name)
+	return nil
+}
+
+// DeleteModel deletes a Casbin model. A model still used by permissions is only deleted with force.
+func (um *UserManager) DeleteModel(name string, force bool) error {
+	casbinModel, err := um.findModel(name)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := um.client.GetPermissions()
+	if err != nil {
+		return err
+	}
+	var usedBy []string
+	for _, permission := range permissions {
+		if permission.Model == name {
+			usedBy = append(usedBy, permission.Name)
+		}
+	}
+	if len(usedBy) > 0 {
+		if !force {
+			return fmt.Errorf("model %v is used by the permissions %v, use --force to delete it anyway", name, strings.Join(usedBy, ", "))
+		}
+		utils.Colorize(color.YellowString, "[⚠] model %v is used by the permissions %v", name, strings.Join(usedBy, ", "))
+	}
+
+	_, err = um.client.DeleteModel(casbinModel)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v model has been deleted successfully", name)
+	return nil
+}
+
+// ValidateModelText parses a Casbin model and checks it the way an enforcer would use it: the
+// request, policy, effect and matcher sections must be defined, the effect must be supported, and
+// every matcher must be a valid expression only referring to defined request and policy tokens,
+// role definitions, Casbin functions and eval().
+func ValidateModelText(text string) error {
+	casbinModel, err := model.NewModelFromString(text)
+	if err != nil {
+		return err
+	}
+
+	for _, assertion := range casbinModel["e"] {
+		if !containsString(supportedEffects, assertion.Value) {
+			return fmt.Errorf("unsupported effect %v = %v (expected one of %v)", assertion.Key, assertion.Value, strings.Join(supportedEffects, ", "))
+		}
+	}
+
+	functionMap := model.LoadFunctionMap()
+	functions := functionMap.GetFunctions()
+	for key, assertion := range casbinModel["g"] {
+		if count := strings.Count(assertion.Value, "_"); count < 2 {
+			return fmt.Errorf("invalid role definition %v = %v (expected at least _, _)", key, assertion.Value)
+		}
+		functions[key] = func(args ...interface{}) (interface{}, error) { return false, nil }
+	}
+	// eval(p.sub_rule) is replaced by the rule of each policy at enforcement time, so only the
+	// policy token it is given is checked
+	functions["eval"] = func(args ...interface{}) (interface{}, error) { return false, nil }
+
+	tokens := map[string]bool{}
+	for _, section := range []string{"r", "p"} {
+		for _, assertion := range casbinModel[section] {
+			for _, token := range assertion.Tokens {
+				tokens[token] = true
+			}
+		}
+	}
+
+	for key, assertion := range casbinModel["m"] {
+		expression, err := govaluate.NewEvaluableExpressionWithFunctions(util.RemoveComments(util.EscapeAssertion(assertion.Value)), functions)
+		if err != nil {
+			return fmt.Errorf("invalid matcher %v = %v: %v", key, assertion.Value, err)
+		}
+		for _, variable := range expression.Vars() {
+			// fields of a request token (r_sub.Owner) are checked at enforcement time
+			token := strings.SplitN(variable, ".", 2)[0]
+			if !tokens[token] {
+				return fmt.Errorf("invalid matcher %v = %v: %v is not defined in the request or policy definition", key, assertion.Value, strings.Replace(token, "_", ".", 1))
+			}
+		}
+	}
+	return nil
+}
+
+func (um *UserManager) findModel(name string) (*casdoorsdk.Model, error) {
+	casbinModel, err := um.client.GetModel(name)
+	if err != nil {
+		return nil, err
+	}
+	if casbinModel == nil {
+		return nil, fmt.Errorf("model %v doesn't exist", name)
+	}
+	return casbinModel, nil
+}
+
+// modelMatcher returns the matcher of the model text, for a short description of the model.
+func modelMatcher(text string) string {
+	casbinModel, err := model.NewModelFromString(text)
+	if err != nil {
+		return "invalid model"
+	}
+	var matchers []string
+	for _, assertion := range casbinModel["m"] {
+		matchers = append(matchers, assertion.Value)
+	}
+	sort.Strings(matchers)
+	return strings.Join(matchers, "; ")
+}
+
+func readModelFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return normalizeModelText(string(data)), nil
+}
+
+func normalizeModelText(text string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")) + "\n"
+}
@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateModelText(t *testing.T) {
+	const rbac = `[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && keyMatch(r.obj, p.obj) && r.act == p.act
+`
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "rbac", text: rbac},
+		{name: "abac with eval", text: strings.Replace(strings.Replace(rbac, "p = sub, obj, act", "p = sub_rule, obj, act", 1), "g(r.sub, p.sub)", "eval(p.sub_rule)", 1)},
+		{name: "abac with request fields", text: strings.Replace(rbac, "g(r.sub, p.sub)", "r.sub.Owner == r.obj.Owner", 1)},
+		{name: "unknown token", text: strings.Replace(rbac, "r.act == p.act", "r.act == p.action", 1), wantErr: "p.action is not defined"},
+		{name: "unknown function", text: strings.Replace(rbac, "keyMatch(", "noMatch(", 1), wantErr: "invalid matcher"},
+		{name: "unsupported effect", text: strings.Replace(rbac, "some(where (p.eft == allow))", "any(p.eft)", 1), wantErr: "unsupported effect"},
+		{name: "invalid role definition", text: strings.Replace(rbac, "g = _, _", "g = _", 1), wantErr: "invalid role definition"},
+		{name: "missing matcher", text: strings.Split(rbac, "[matchers]")[0], wantErr: "missing"},
+	}
+	for _, test := range tests {
+		err := ValidateModelText(test.text)
+		if test.wantErr == "" && err != nil {
+			t.Errorf("%v: ValidateModelText failed: %v", test.name, err)
+		}
+		if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+			t.Errorf("%v: ValidateModelText error = %v, want an error containing %q", test.name, err, test.wantErr)
+		}
+	}
+}
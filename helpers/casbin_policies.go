@@ -0,0 +1,278 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"io"
+	"os"
+	"strings"
+)
+
+// GetPolicies returns the policy rules of an enforcer.
+func (um *UserManager) GetPolicies(enforcerName string) ([]map[string]interface{}, error) {
+	rules, err := um.enforcerPolicies(enforcerName)
+	if err != nil {
+		return nil, err
+	}
+	var policyList []map[string]interface{}
+
+	for _, rule := range rules {
+		values := policyValues(rule)
+		policyList = append(policyList, map[string]interface{}{
+			"Type": values[0],
+			"Rule": FormatPolicyLine(values[1:]),
+		})
+	}
+	return policyList, nil
+}
+
+// AddPolicy adds a policy rule such as "p, alice, data1, read" to an enforcer.
+func (um *UserManager) AddPolicy(enforcerName string, line string) error {
+	enforcer, err := um.findEnforcer(enforcerName)
+	if err != nil {
+		return err
+	}
+	values, err := ParsePolicyLine(line)
+	if err != nil {
+		return err
+	}
+	rules, err := um.enforcerPolicies(enforcerName)
+	if err != nil {
+		return err
+	}
+	if containsPolicy(rules, values) {
+		utils.Colorize(color.YellowString, "[⚠] %v already exists in %v", FormatPolicyLine(values), enforcerName)
+		return nil
+	}
+
+	_, err = um.client.AddPolicy(enforcer, policyRule(values))
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v has been added to %v", FormatPolicyLine(values), enforcerName)
+	return nil
+}
+
+// RemovePolicy removes a policy rule such as "p, alice, data1, read" from an enforcer.
+func (um *UserManager) RemovePolicy(enforcerName string, line string) error {
+	enforcer, err := um.findEnforcer(enforcerName)
+	if err != nil {
+		return err
+	}
+	values, err := ParsePolicyLine(line)
+	if err != nil {
+		return err
+	}
+	rules, err := um.enforcerPolicies(enforcerName)
+	if err != nil {
+		return err
+	}
+	if !containsPolicy(rules, values) {
+		return fmt.Errorf("%v doesn't exist in %v", FormatPolicyLine(values), enforcerName)
+	}
+
+	_, err = um.client.RemovePolicy(enforcer, policyRule(values))
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v has been removed from %v", FormatPolicyLine(values), enforcerName)
+	return nil
+}
+
+// ExportPolicies writes the policy rules of an enforcer as Casbin policy CSV lines.
+func (um *UserManager) ExportPolicies(enforcerName string, writer io.Writer) (int, error) {
+	rules, err := um.enforcerPolicies(enforcerName)
+	if err != nil {
+		return 0, err
+	}
+	for _, rule := range rules {
+		if _, err = fmt.Fprintln(writer, FormatPolicyLine(policyValues(rule))); err != nil {
+			return 0, err
+		}
+	}
+	return len(rules), nil
+}
+
+// ImportPolicies reconciles the policy rules of an enforcer with a Casbin policy CSV file. Rules
+// missing from the enforcer are added, and with prune, rules absent from the file are removed.
+// With dryRun, the changes are only shown.
+func (um *UserManager) ImportPolicies(enforcerName string, path string, prune bool, dryRun bool) error {
+	enforcer, err := um.findEnforcer(enforcerName)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	wanted, err := ReadPolicyLines(file)
+	if err != nil {
+		return fmt.Errorf("error reading %v: %v", path, err)
+	}
+	rules, err := um.enforcerPolicies(enforcerName)
+	if err != nil {
+		return err
+	}
+
+	var toAdd [][]string
+	for _, values := range wanted {
+		if !containsPolicy(rules, values) && !containsValues(toAdd, values) {
+			toAdd = append(toAdd, values)
+		}
+	}
+	var toRemove [][]string
+	if prune {
+		for _, rule := range rules {
+			if values := policyValues(rule); !containsValues(wanted, values) {
+				toRemove = append(toRemove, values)
+			}
+		}
+	}
+
+	for _, values := range toAdd {
+		utils.Colorize(color.GreenString, "+ %v", FormatPolicyLine(values))
+	}
+	for _, values := range toRemove {
+		utils.Colorize(color.RedString, "- %v", FormatPolicyLine(values))
+	}
+	if len(toAdd) == 0 && len(toRemove) == 0 {
+		utils.Colorize(color.GreenString, "[✔] %v is already up to date", enforcerName)
+		return nil
+	}
+	if dryRun {
+		return nil
+	}
+
+	for _, values := range toAdd {
+		if _, err = um.client.AddPolicy(enforcer, policyRule(values)); err != nil {
+			return fmt.Errorf("adding %v failed: %v", FormatPolicyLine(values), err)
+		}
+	}
+	for _, values := range toRemove {
+		if _, err = um.client.RemovePolicy(enforcer, policyRule(values)); err != nil {
+			return fmt.Errorf("removing %v failed: %v", FormatPolicyLine(values), err)
+		}
+	}
+	utils.Colorize(color.GreenString, "[✔] %d policies added and %d removed from %v", len(toAdd), len(toRemove), enforcerName)
+	return nil
+}
+
+// ParsePolicyLine parses a Casbin policy CSV line such as "p, alice, data1, read" into its
+// policy type followed by its values.
+func ParsePolicyLine(line string) ([]string, error) {
+	lines, err := ReadPolicyLines(strings.NewReader(line))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) != 1 {
+		return nil, fmt.Errorf("expected a single policy line such as \"p, alice, data1, read\"")
+	}
+	return lines[0], nil
+}
+
+// ReadPolicyLines reads Casbin policy CSV lines. Empty lines and "#" comments are skipped.
+func ReadPolicyLines(reader io.Reader) ([][]string, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var lines [][]string
+	for _, record := range records {
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+		for len(record) > 1 && record[len(record)-1] == "" {
+			record = record[:len(record)-1]
+		}
+		if len(record) < 2 || record[0] == "" {
+			return nil, fmt.Errorf("invalid policy line %q (expected a policy type and values)", strings.Join(record, ", "))
+		}
+		if len(record) > 7 {
+			return nil, fmt.Errorf("invalid policy line %q (at most 6 values are supported)", strings.Join(record, ", "))
+		}
+		lines = append(lines, record)
+	}
+	return lines, nil
+}
+
+// FormatPolicyLine formats a policy type and its values as a Casbin policy CSV line.
+func FormatPolicyLine(values []string) string {
+	var fields []string
+	for _, value := range values {
+		if strings.ContainsAny(value, ",\"") {
+			value = "\"" + strings.ReplaceAll(value, "\"", "\"\"") + "\""
+		}
+		fields = append(fields, value)
+	}
+	return strings.Join(fields, ", ")
+}
+
+func (um *UserManager) enforcerPolicies(enforcerName string) ([]*casdoorsdk.CasbinRule, error) {
+	if _, err := um.findEnforcer(enforcerName); err != nil {
+		return nil, err
+	}
+	return um.client.GetPolicies(enforcerName, "")
+}
+
+// policyValues returns the policy type of the rule followed by its values, without the
+// trailing empty values.
+func policyValues(rule *casdoorsdk.CasbinRule) []string {
+	values := []string{rule.Ptype, rule.V0, rule.V1, rule.V2, rule.V3, rule.V4, rule.V5}
+	for len(values) > 1 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+	return values
+}
+
+func policyRule(values []string) *casdoorsdk.CasbinRule {
+	padded := make([]string, 7)
+	copy(padded, values)
+	return &casdoorsdk.CasbinRule{
+		Ptype: padded[0],
+		V0:    padded[1],
+		V1:    padded[2],
+		V2:    padded[3],
+		V3:    padded[4],
+		V4:    padded[5],
+		V5:    padded[6],
+	}
+}
+
+func containsPolicy(rules []*casdoorsdk.CasbinRule, values []string) bool {
+	for _, rule := range rules {
+		if equalValues(policyValues(rule), values) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValues(lines [][]string, values []string) bool {
+	for _, line := range lines {
+		if equalValues(line, values) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalValues(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePolicyLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "p, alice, data1, read", want: []string{"p", "alice", "data1", "read"}},
+		{line: "p,alice,data1,read", want: []string{"p", "alice", "data1", "read"}},
+		{line: "g, alice, admin, , ", want: []string{"g", "alice", "admin"}},
+		{line: `p, alice, "data1, data2", read`, want: []string{"p", "alice", "data1, data2", "read"}},
+		{line: `p, alice, "say ""hi""", read`, want: []string{"p", "alice", `say "hi"`, "read"}},
+		{line: "p", wantErr: true},
+		{line: ", alice, data1", wantErr: true},
+		{line: "p, 1, 2, 3, 4, 5, 6, 7", wantErr: true},
+		{line: "p, alice, data1, read\np, bob, data2, write", wantErr: true},
+		{line: "# a comment", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParsePolicyLine(test.line)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ParsePolicyLine(%q) error = %v, want error %v", test.line, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParsePolicyLine(%q) = %q, want %q", test.line, got, test.want)
+		}
+	}
+}
+
+func TestFormatPolicyLine(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   string
+	}{
+		{[]string{"p", "alice", "data1", "read"}, "p, alice, data1, read"},
+		{[]string{"p", "alice", "data1, data2", "read"}, `p, alice, "data1, data2", read`},
+		{[]string{"p", "alice", `say "hi"`, "read"}, `p, alice, "say ""hi""", read`},
+	}
+	for _, test := range tests {
+		got := FormatPolicyLine(test.values)
+		if got != test.want {
+			t.Errorf("FormatPolicyLine(%q) = %q, want %q", test.values, got, test.want)
+		}
+		parsed, err := ParsePolicyLine(got)
+		if err != nil || !reflect.DeepEqual(parsed, test.values) {
+			t.Errorf("ParsePolicyLine(%q) = %q, %v, want %q", got, parsed, err, test.values)
+		}
+	}
+}
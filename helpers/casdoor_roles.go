@@ -0,0 +1,410 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RoleFields holds the fields of a Casdoor role besides its name. Users and Roles are names
+// within the organization, and Roles are the sub-roles whose members inherit the role.
+type RoleFields struct {
+	DisplayName string
+	Description string
+	Users       []string
+	Roles       []string
+	Domains     []string
+	Enabled     bool
+}
+
+// RoleUpdate holds the fields to change on a role. Nil fields are preserved.
+type RoleUpdate struct {
+	DisplayName *string
+	Description *string
+	Domains     *[]string
+	Enabled     *bool
+}
+
+// GetRoles returns the Casdoor roles of the organization.
+func (um *UserManager) GetRoles() ([]map[string]interface{}, error) {
+	roles, err := um.client.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	var roleList []map[string]interface{}
+
+	for _, role := range roles {
+		roleList = append(roleList, roleInfo(role))
+	}
+	return roleList, nil
+}
+
+// GetRole returns a role along with its effective users, which include the members of
+// its sub-roles.
+func (um *UserManager) GetRole(name string) (map[string]interface{}, error) {
+	roles, err := um.client.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	rolesByName := map[string]*casdoorsdk.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+	role, ok := rolesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("role %v doesn't exist", name)
+	}
+
+	info := roleInfo(role)
+	info["Description"] = role.Description
+	info["EffectiveUsers"] = strings.Join(effectiveRoleUsers(rolesByName, name, map[string]bool{}), ", ")
+	return info, nil
+}
+
+// AddRole creates a Casdoor role.
+func (um *UserManager) AddRole(name string, fields RoleFields) error {
+	existing, err := um.client.GetRole(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("role %v already exists", name)
+	}
+	if err = um.checkUsersExist(fields.Users); err != nil {
+		return err
+	}
+	if err = um.checkRolesExist(fields.Roles); err != nil {
+		return err
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	role := casdoorsdk.Role{
+		Owner:       um.client.OrganizationName,
+		Name:        name,
+		CreatedTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName: displayName,
+		Description: fields.Description,
+		Users:       um.groupIds(fields.Users),
+		Roles:       um.groupIds(fields.Roles),
+		Domains:     nonNilList(fields.Domains),
+		IsEnabled:   fields.Enabled,
+	}
+	_, err = um.client.AddRole(&role)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v role has been created successfully", name)
+	return nil
+}
+
+// UpdateRole changes the given fields of a role and preserves the other ones.
+func (um *UserManager) UpdateRole(name string, update RoleUpdate) error {
+	role, err := um.findRole(name)
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	if update.DisplayName != nil {
+		role.DisplayName = *update.DisplayName
+		columns = append(columns, "display_name")
+	}
+	if update.Description != nil {
+		role.Description = *update.Description
+		columns = append(columns, "description")
+	}
+	if update.Domains != nil {
+		role.Domains = nonNilList(*update.Domains)
+		columns = append(columns, "domains")
+	}
+	if update.Enabled != nil {
+		role.IsEnabled = *update.Enabled
+		columns = append(columns, "is_enabled")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("nothing to update on role %v", name)
+	}
+
+	_, err = um.client.UpdateRoleForColumns(role, columns)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v role has been updated successfully", name)
+	return nil
+}
+
+// DeleteRole deletes a Casdoor role. It is also removed from the roles using it as a sub-role.
+func (um *UserManager) DeleteRole(name string) error {
+	roles, err := um.client.GetRoles()
+	if err != nil {
+		return err
+	}
+	var role *casdoorsdk.Role
+	for _, candidate := range roles {
+		if candidate.Name == name {
+			role = candidate
+		}
+	}
+	if role == nil {
+		return fmt.Errorf("role %v doesn't exist", name)
+	}
+
+	for _, parent := range roles {
+		subRoles := groupNames(parent.Roles)
+		if parent.Name == name || !containsString(subRoles, name) {
+			continue
+		}
+		parent.Roles = um.groupIds(removeStrings(subRoles, []string{name}))
+		if _, err = um.client.UpdateRoleForColumns(parent, []string{"roles"}); err != nil {
+			return err
+		}
+		utils.Colorize(color.CyanString, "[ℹ] %v is no longer a sub-role of %v", name, parent.Name)
+	}
+
+	_, err = um.client.DeleteRole(role)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v role has been deleted successfully", name)
+	return nil
+}
+
+// AddRoleMembers adds users and sub-roles to a role, keeping its current members.
+func (um *UserManager) AddRoleMembers(name string, users []string, subRoles []string) error {
+	role, err := um.findRole(name)
+	if err != nil {
+		return err
+	}
+	if err = um.checkUsersExist(users); err != nil {
+		return err
+	}
+	if err = um.checkRolesExist(subRoles); err != nil {
+		return err
+	}
+	if len(subRoles) > 0 {
+		roles, err := um.client.GetRoles()
+		if err != nil {
+			return err
+		}
+		if err = checkRoleCycle(roles, name, subRoles); err != nil {
+			return err
+		}
+	}
+
+	currentUsers := groupNames(role.Users)
+	for _, user := range users {
+		if !containsString(currentUsers, user) {
+			currentUsers = append(currentUsers, user)
+		}
+	}
+	currentRoles := groupNames(role.Roles)
+	for _, subRole := range subRoles {
+		if !containsString(currentRoles, subRole) {
+			currentRoles = append(currentRoles, subRole)
+		}
+	}
+	return um.updateRoleMembers(role, currentUsers, currentRoles)
+}
+
+// RemoveRoleMembers removes users and sub-roles from a role.
+func (um *UserManager) RemoveRoleMembers(name string, users []string, subRoles []string) error {
+	role, err := um.findRole(name)
+	if err != nil {
+		return err
+	}
+	currentUsers := groupNames(role.Users)
+	currentRoles := groupNames(role.Roles)
+	for _, member := range append(append([]string{}, users...), subRoles...) {
+		if !containsString(currentUsers, member) && !containsString(currentRoles, member) {
+			utils.Colorize(color.YellowString, "[⚠] %v is not a member of %v", member, name)
+		}
+	}
+	return um.updateRoleMembers(role, removeStrings(currentUsers, users), removeStrings(currentRoles, subRoles))
+}
+
+// GetRoleTree renders the role inheritance of the organization. Sub-roles are shown under the
+// roles they inherit, along with the number of direct and effective users of each role.
+func (um *UserManager) GetRoleTree() (string, error) {
+	roles, err := um.client.GetRoles()
+	if err != nil {
+		return "", err
+	}
+	rolesByName := map[string]*casdoorsdk.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+
+	isSubRole := map[string]bool{}
+	for _, role := range roles {
+		for _, subRole := range groupNames(role.Roles) {
+			if subRole != role.Name {
+				isSubRole[subRole] = true
+			}
+		}
+	}
+	var topRoles []string
+	for _, role := range roles {
+		if !isSubRole[role.Name] {
+			topRoles = append(topRoles, role.Name)
+		}
+	}
+
+	var tree strings.Builder
+	tree.WriteString(um.client.OrganizationName + "\n")
+	var render func(names []string, prefix string, path map[string]bool)
+	render = func(names []string, prefix string, path map[string]bool) {
+		sort.Strings(names)
+		for i, name := range names {
+			branch, indent := "├── ", "│   "
+			if i == len(names)-1 {
+				branch, indent = "└── ", "    "
+			}
+			role, ok := rolesByName[name]
+			if !ok {
+				tree.WriteString(fmt.Sprintf("%s%s%s (missing)\n", prefix, branch, name))
+				continue
+			}
+			tree.WriteString(prefix + branch + name)
+			if len(role.Domains) > 0 {
+				tree.WriteString(fmt.Sprintf(" [%s]", strings.Join(role.Domains, ", ")))
+			}
+			if !role.IsEnabled {
+				tree.WriteString(" (disabled)")
+			}
+			if path[name] {
+				tree.WriteString(" - cycle\n")
+				continue
+			}
+			effective := effectiveRoleUsers(rolesByName, name, map[string]bool{})
+			tree.WriteString(fmt.Sprintf(" - %d users, %d effective\n", len(role.Users), len(effective)))
+			path[name] = true
+			render(groupNames(role.Roles), prefix+indent, path)
+			delete(path, name)
+		}
+	}
+	render(topRoles, "", map[string]bool{})
+
+	return tree.String(), nil
+}
+
+func (um *UserManager) updateRoleMembers(role *casdoorsdk.Role, users []string, subRoles []string) error {
+	role.Users = um.groupIds(users)
+	role.Roles = um.groupIds(subRoles)
+	_, err := um.client.UpdateRoleForColumns(role, []string{"users", "roles"})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v role now has users: %v and sub-roles: %v", role.Name, strings.Join(users, ", "), strings.Join(subRoles, ", "))
+	return nil
+}
+
+func (um *UserManager) findRole(name string) (*casdoorsdk.Role, error) {
+	role, err := um.client.GetRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("role %v doesn't exist", name)
+	}
+	return role, nil
+}
+
+func (um *UserManager) checkRolesExist(names []string) error {
+	for _, name := range names {
+		if _, err := um.findRole(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (um *UserManager) checkUsersExist(names []string) error {
+	for _, name := range names {
+		if _, err := um.findUser(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoleCycle returns an error if adding the sub-roles to the role would make the role inherit itself.
+func checkRoleCycle(roles []*casdoorsdk.Role, name string, subRoles []string) error {
+	rolesByName := map[string]*casdoorsdk.Role{}
+	for _, role := range roles {
+		rolesByName[role.Name] = role
+	}
+	for _, subRole := range subRoles {
+		if subRole == name || containsString(descendantRoles(rolesByName, subRole, map[string]bool{}), name) {
+			return fmt.Errorf("%v cannot be a sub-role of %v as it would create a cycle", subRole, name)
+		}
+	}
+	return nil
+}
+
+// descendantRoles returns every role inherited, directly or not, by the role.
+func descendantRoles(rolesByName map[string]*casdoorsdk.Role, name string, visited map[string]bool) []string {
+	role, ok := rolesByName[name]
+	if !ok || visited[name] {
+		return nil
+	}
+	visited[name] = true
+	var descendants []string
+	for _, subRole := range groupNames(role.Roles) {
+		descendants = append(descendants, subRole)
+		descendants = append(descendants, descendantRoles(rolesByName, subRole, visited)...)
+	}
+	return descendants
+}
+
+// effectiveRoleUsers returns the users of the role and of all its sub-roles.
+func effectiveRoleUsers(rolesByName map[string]*casdoorsdk.Role, name string, visited map[string]bool) []string {
+	var users []string
+	for _, roleName := range append([]string{name}, descendantRoles(rolesByName, name, visited)...) {
+		role, ok := rolesByName[roleName]
+		if !ok {
+			continue
+		}
+		for _, user := range groupNames(role.Users) {
+			if !containsString(users, user) {
+				users = append(users, user)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
+func roleInfo(role *casdoorsdk.Role) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":        role.Name,
+		"DisplayName": role.DisplayName,
+		"Users":       strings.Join(groupNames(role.Users), ", "),
+		"SubRoles":    strings.Join(groupNames(role.Roles), ", "),
+		"Domains":     strings.Join(role.Domains, ", "),
+		"Enabled":     role.IsEnabled,
+	}
+}
+
+func removeStrings(items []string, removed []string) []string {
+	kept := []string{}
+	for _, item := range items {
+		if !containsString(removed, item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func nonNilList(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
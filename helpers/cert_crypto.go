@@ -0,0 +1,191 @@
+package helpers
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// CertAlgorithms are the signing algorithms of the certificates generated by the CLI.
+var CertAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// GeneratedCert is a self-signed certificate and its private key, both PEM-encoded.
+type GeneratedCert struct {
+	Certificate string
+	PrivateKey  string
+	BitSize     int
+}
+
+// GenerateSelfSignedCert generates a key pair and a self-signed certificate locally, the way Casdoor
+// does for its own certificates. RSA keys use bitSize bits, while ECDSA keys use the curve of the
+// algorithm.
+func GenerateSelfSignedCert(organization string, name string, algorithm string, bitSize int, expireInYears int) (*GeneratedCert, error) {
+	if expireInYears <= 0 {
+		return nil, fmt.Errorf("expiry must be a positive number of years")
+	}
+
+	var signer crypto.Signer
+	var keyBlock *pem.Block
+	switch algorithm {
+	case "RS256", "RS384", "RS512":
+		if bitSize < 2048 {
+			return nil, fmt.Errorf("RSA keys need at least 2048 bits")
+		}
+		key, err := rsa.GenerateKey(rand.Reader, bitSize)
+		if err != nil {
+			return nil, err
+		}
+		signer = key
+		keyBlock = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case "ES256", "ES384", "ES512":
+		curve := map[string]elliptic.Curve{"ES256": elliptic.P256(), "ES384": elliptic.P384(), "ES512": elliptic.P521()}[algorithm]
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		bytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		signer = key
+		keyBlock = &pem.Block{Type: "EC PRIVATE KEY", Bytes: bytes}
+		bitSize = curve.Params().BitSize
+	default:
+		return nil, fmt.Errorf("invalid algorithm %v (expected one of %v)", algorithm, strings.Join(CertAlgorithms, ", "))
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{organization}, CommonName: name},
+		NotBefore:             now,
+		NotAfter:              now.AddDate(expireInYears, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, signer.Public(), signer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeneratedCert{
+		Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes})),
+		PrivateKey:  string(pem.EncodeToMemory(keyBlock)),
+		BitSize:     bitSize,
+	}, nil
+}
+
+// ParseCertificate parses a PEM-encoded x509 certificate.
+func ParseCertificate(certificate string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// CertJWK returns the JSON Web Key of a certificate, as served by Casdoor on /.well-known/jwks.
+func CertJWK(kid string, algorithm string, certificate string) (map[string]interface{}, error) {
+	cert, err := ParseCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint := sha1.Sum(cert.Raw)
+	jwk := map[string]interface{}{
+		"kid": kid,
+		"use": "sig",
+		"alg": algorithm,
+		"x5c": []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk["kty"] = "RSA"
+		jwk["n"] = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk["kty"] = "EC"
+		jwk["crv"] = key.Curve.Params().Name
+		jwk["x"] = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk["y"] = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", cert.PublicKey)
+	}
+	return jwk, nil
+}
+
+// checkKeyPair checks that a PEM-encoded private key matches the public key of a certificate.
+func checkKeyPair(certificate string, privateKey string) error {
+	cert, err := ParseCertificate(certificate)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return fmt.Errorf("no PEM private key found")
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", key)
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(cert.PublicKey) {
+		return fmt.Errorf("the private key doesn't match the certificate")
+	}
+	return nil
+}
+
+// publicKeyBitSize returns the size of an RSA modulus or of an ECDSA curve.
+func publicKeyBitSize(publicKey crypto.PublicKey) int {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return key.Curve.Params().BitSize
+	}
+	return 0
+}
+
+// defaultCertAlgorithm returns the algorithm Casdoor uses by default with a public key.
+func defaultCertAlgorithm(publicKey crypto.PublicKey) string {
+	if key, ok := publicKey.(*ecdsa.PublicKey); ok {
+		switch key.Curve.Params().BitSize {
+		case 384:
+			return "ES384"
+		case 521:
+			return "ES512"
+		}
+		return "ES256"
+	}
+	return "RS256"
+}
@@ -0,0 +1,391 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+	"time"
+)
+
+// CertFields holds the fields of a new certificate besides its name. Certificate and PrivateKey are PEM-encoded.
+type CertFields struct {
+	DisplayName     string
+	Scope           string
+	CryptoAlgorithm string
+	Certificate     string
+	PrivateKey      string
+}
+
+// CertUpdate holds the fields to change on a certificate. Nil fields are preserved.
+type CertUpdate struct {
+	DisplayName *string
+	Scope       *string
+	Certificate *string
+	PrivateKey  *string
+}
+
+// GetCerts returns the certificates of the organization, or every certificate with global, along
+// with their expiry. Private keys are never returned.
+func (um *UserManager) GetCerts(global bool) ([]map[string]interface{}, error) {
+	certs, err := um.listCerts(global)
+	if err != nil {
+		return nil, err
+	}
+	var certList []map[string]interface{}
+
+	for _, cert := range certs {
+		certList = append(certList, map[string]interface{}{
+			"Name":            cert.Name,
+			"Owner":           cert.Owner,
+			"Scope":           cert.Scope,
+			"CryptoAlgorithm": cert.CryptoAlgorithm,
+			"BitSize":         cert.BitSize,
+			"ExpiresAt":       certExpiry(cert),
+		})
+	}
+	return certList, nil
+}
+
+// GetCert returns the details of a certificate. Its private key is never returned.
+func (um *UserManager) GetCert(name string) (map[string]interface{}, error) {
+	cert, err := um.findCert(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := map[string]interface{}{
+		"Name":            cert.Name,
+		"Owner":           cert.Owner,
+		"DisplayName":     cert.DisplayName,
+		"Scope":           cert.Scope,
+		"Type":            cert.Type,
+		"CryptoAlgorithm": cert.CryptoAlgorithm,
+		"BitSize":         cert.BitSize,
+		"ExpiresAt":       certExpiry(cert),
+	}
+	if parsed, err := ParseCertificate(cert.Certificate); err == nil {
+		info["Subject"] = parsed.Subject.String()
+		info["NotBefore"] = parsed.NotBefore.UTC().Format(time.RFC3339)
+	}
+	return info, nil
+}
+
+// AddCert uploads a certificate and its private key to the organization.
+func (um *UserManager) AddCert(name string, fields CertFields) error {
+	existing, err := um.client.GetCert(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("cert %v already exists", name)
+	}
+	if err = checkKeyPair(fields.Certificate, fields.PrivateKey); err != nil {
+		return err
+	}
+	parsed, err := ParseCertificate(fields.Certificate)
+	if err != nil {
+		return err
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	algorithm := fields.CryptoAlgorithm
+	if algorithm == "" {
+		algorithm = defaultCertAlgorithm(parsed.PublicKey)
+	}
+	_, err = um.client.AddCert(&casdoorsdk.Cert{
+		Owner:           um.client.OrganizationName,
+		Name:            name,
+		CreatedTime:     time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName:     displayName,
+		Scope:           fields.Scope,
+		Type:            "x509",
+		CryptoAlgorithm: algorithm,
+		BitSize:         publicKeyBitSize(parsed.PublicKey),
+		ExpireInYears:   certYears(parsed.NotBefore, parsed.NotAfter),
+		Certificate:     fields.Certificate,
+		PrivateKey:      fields.PrivateKey,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v cert has been created successfully", name)
+	return nil
+}
+
+// UpdateCert changes the given fields of a certificate. A new certificate or private key must match
+// the other one, so that the key pair stays usable. Like on deletion, the global certificates of
+// admin are left alone.
+func (um *UserManager) UpdateCert(name string, update CertUpdate) error {
+	cert, err := um.findCert(name)
+	if err != nil {
+		return err
+	}
+	if err = um.checkCertOwner(cert, "updated"); err != nil {
+		return err
+	}
+	if update.DisplayName == nil && update.Scope == nil && update.Certificate == nil && update.PrivateKey == nil {
+		return fmt.Errorf("nothing to update on cert %v", name)
+	}
+
+	setIfNotNil(&cert.DisplayName, update.DisplayName)
+	setIfNotNil(&cert.Scope, update.Scope)
+	if update.Certificate != nil || update.PrivateKey != nil {
+		setIfNotNil(&cert.Certificate, update.Certificate)
+		setIfNotNil(&cert.PrivateKey, update.PrivateKey)
+		if err = checkKeyPair(cert.Certificate, cert.PrivateKey); err != nil {
+			return err
+		}
+		parsed, err := ParseCertificate(cert.Certificate)
+		if err != nil {
+			return err
+		}
+		cert.BitSize = publicKeyBitSize(parsed.PublicKey)
+		cert.ExpireInYears = certYears(parsed.NotBefore, parsed.NotAfter)
+	}
+
+	err = um.modifyCertById("update-cert", cert)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v cert has been updated successfully", name)
+	return nil
+}
+
+// GenerateCert generates a key pair and a self-signed certificate locally and uploads them. With
+// replace, the key pair of an existing certificate is rotated instead.
+func (um *UserManager) GenerateCert(name string, algorithm string, bitSize int, expireInYears int, replace bool) error {
+	existing, err := um.client.GetCert(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && !replace {
+		return fmt.Errorf("cert %v already exists, use --replace to rotate its key pair", name)
+	}
+	if existing == nil && replace {
+		return fmt.Errorf("cert %v doesn't exist", name)
+	}
+
+	generated, err := GenerateSelfSignedCert(um.client.OrganizationName, name, algorithm, bitSize, expireInYears)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		_, err = um.client.AddCert(&casdoorsdk.Cert{
+			Owner:           um.client.OrganizationName,
+			Name:            name,
+			CreatedTime:     time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			DisplayName:     name,
+			Scope:           "JWT",
+			Type:            "x509",
+			CryptoAlgorithm: algorithm,
+			BitSize:         generated.BitSize,
+			ExpireInYears:   expireInYears,
+			Certificate:     generated.Certificate,
+			PrivateKey:      generated.PrivateKey,
+		})
+		if err != nil {
+			return err
+		}
+		utils.Colorize(color.GreenString, "[✔] %v cert has been generated successfully", name)
+		return nil
+	}
+
+	existing.CryptoAlgorithm = algorithm
+	existing.BitSize = generated.BitSize
+	existing.ExpireInYears = expireInYears
+	existing.Certificate = generated.Certificate
+	existing.PrivateKey = generated.PrivateKey
+	err = um.modifyCertById("update-cert", existing)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] key pair of %v has been rotated successfully", name)
+	utils.Colorize(color.YellowString, "[⚠] tokens signed with the previous key won't be valid anymore")
+	return nil
+}
+
+// DeleteCert deletes a certificate. A certificate still used by applications is only deleted with force.
+// The global certificates of admin may be used by the applications of any organization, so only the
+// certificates of the organization can be deleted.
+func (um *UserManager) DeleteCert(name string, force bool) error {
+	cert, err := um.findCert(name)
+	if err != nil {
+		return err
+	}
+	if err = um.checkCertOwner(cert, "deleted"); err != nil {
+		return err
+	}
+
+	applications, err := um.client.GetOrganizationApplications()
+	if err != nil {
+		return err
+	}
+	var usedBy []string
+	for _, application := range applications {
+		if application.Cert == name {
+			usedBy = append(usedBy, application.Name)
+		}
+	}
+	if len(usedBy) > 0 {
+		if !force {
+			return fmt.Errorf("cert %v is used by the applications %v, use --force to delete it anyway", name, strings.Join(usedBy, ", "))
+		}
+		utils.Colorize(color.YellowString, "[⚠] cert %v is used by the applications %v", name, strings.Join(usedBy, ", "))
+	}
+
+	err = um.modifyCertById("delete-cert", cert)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v cert has been deleted successfully", name)
+	return nil
+}
+
+// ExportCert returns the public part of a certificate, either PEM-encoded or as a JSON Web Key Set.
+func (um *UserManager) ExportCert(name string, jwks bool) (string, error) {
+	cert, err := um.findCert(name)
+	if err != nil {
+		return "", err
+	}
+	if !jwks {
+		return strings.TrimRight(cert.Certificate, "\n") + "\n", nil
+	}
+
+	jwk, err := CertJWK(cert.Name, cert.CryptoAlgorithm, cert.Certificate)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{"keys": []interface{}{jwk}}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data) + "\n", nil
+}
+
+// CheckCerts returns the certificates along with the number of days before they expire, and the
+// number of certificates expiring within days.
+func (um *UserManager) CheckCerts(global bool, days int) ([]map[string]interface{}, int, error) {
+	certs, err := um.listCerts(global)
+	if err != nil {
+		return nil, 0, err
+	}
+	var certList []map[string]interface{}
+	expiring := 0
+
+	for _, cert := range certs {
+		status := "OK"
+		daysLeft := "unknown"
+		parsed, err := ParseCertificate(cert.Certificate)
+		if err != nil {
+			status = "INVALID"
+			expiring++
+		} else {
+			left := int(time.Until(parsed.NotAfter).Hours() / 24)
+			daysLeft = fmt.Sprintf("%d", left)
+			if left < 0 {
+				status = "EXPIRED"
+				expiring++
+			} else if left <= days {
+				status = "EXPIRING"
+				expiring++
+			}
+		}
+		certList = append(certList, map[string]interface{}{
+			"Name":      cert.Name,
+			"Owner":     cert.Owner,
+			"ExpiresAt": certExpiry(cert),
+			"DaysLeft":  daysLeft,
+			"Status":    status,
+		})
+	}
+	return certList, expiring, nil
+}
+
+// GetApplicationCertificate returns the PEM-encoded certificate an application signs its tokens with.
+func (um *UserManager) GetApplicationCertificate(application string) (string, error) {
+	app, err := um.findApplication(application)
+	if err != nil {
+		return "", err
+	}
+	if app.Cert == "" {
+		return "", fmt.Errorf("application %v has no cert", application)
+	}
+	cert, err := um.findCert(app.Cert)
+	if err != nil {
+		return "", err
+	}
+	return cert.Certificate, nil
+}
+
+func (um *UserManager) listCerts(global bool) ([]*casdoorsdk.Cert, error) {
+	if global {
+		return um.client.GetGlobalCerts()
+	}
+	return um.client.GetCerts()
+}
+
+// checkCertOwner refuses to change the certificates of another organization, such as the global
+// ones of admin which the applications of every organization may sign their tokens with.
+func (um *UserManager) checkCertOwner(cert *casdoorsdk.Cert, action string) error {
+	if cert.Owner != um.client.OrganizationName {
+		return fmt.Errorf("cert %v belongs to %v, only the certs of %v can be %v", cert.Name, cert.Owner, um.client.OrganizationName, action)
+	}
+	return nil
+}
+
+// findCert looks for a certificate in the organization first, and then among the global ones,
+// such as cert-built-in which belongs to admin.
+func (um *UserManager) findCert(name string) (*casdoorsdk.Cert, error) {
+	cert, err := um.client.GetCert(name)
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		return cert, nil
+	}
+
+	certs, err := um.client.GetGlobalCerts()
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range certs {
+		if cert.Name == name && cert.Owner == "admin" {
+			return cert, nil
+		}
+	}
+	return nil, fmt.Errorf("cert %v doesn't exist", name)
+}
+
+// modifyCertById posts a certificate with its own owner, as the SDK always sets the organization
+// as the owner, which would miss global certificates.
+func (um *UserManager) modifyCertById(action string, cert *casdoorsdk.Cert) error {
+	postBytes, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DoPost(action, map[string]string{"id": fmt.Sprintf("%s/%s", cert.Owner, cert.Name)}, postBytes, false, false)
+	return err
+}
+
+func certExpiry(cert *casdoorsdk.Cert) string {
+	parsed, err := ParseCertificate(cert.Certificate)
+	if err != nil {
+		return "unknown"
+	}
+	return parsed.NotAfter.UTC().Format(time.RFC3339)
+}
+
+func certYears(notBefore time.Time, notAfter time.Time) int {
+	years := notAfter.Year() - notBefore.Year()
+	if years < 1 {
+		return 1
+	}
+	return years
+}
@@ -0,0 +1,256 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+	"time"
+)
+
+// EnforcerFields holds the fields of a Casdoor enforcer besides its name. Model and Adapter are
+// names within the organization, or "<owner>/<name>" ids for shared ones.
+type EnforcerFields struct {
+	DisplayName string
+	Description string
+	Model       string
+	Adapter     string
+}
+
+// AdapterFields holds the fields of a Casdoor adapter besides its name.
+type AdapterFields struct {
+	Type         string
+	DatabaseType string
+	Host         string
+	Port         int
+	User         string
+	Password     string
+	Database     string
+	Table        string
+}
+
+// GetEnforcers returns the Casdoor enforcers of the organization.
+func (um *UserManager) GetEnforcers() ([]map[string]interface{}, error) {
+	enforcers, err := um.client.GetEnforcers()
+	if err != nil {
+		return nil, err
+	}
+	var enforcerList []map[string]interface{}
+
+	for _, enforcer := range enforcers {
+		enforcerList = append(enforcerList, enforcerInfo(enforcer))
+	}
+	return enforcerList, nil
+}
+
+// GetEnforcer returns every field of a Casdoor enforcer.
+func (um *UserManager) GetEnforcer(name string) (map[string]interface{}, error) {
+	enforcer, err := um.findEnforcer(name)
+	if err != nil {
+		return nil, err
+	}
+	info := enforcerInfo(enforcer)
+	info["Description"] = enforcer.Description
+	info["CreatedTime"] = enforcer.CreatedTime
+	return info, nil
+}
+
+// AddEnforcer creates a Casdoor enforcer evaluating the policies of the adapter with the model.
+func (um *UserManager) AddEnforcer(name string, fields EnforcerFields) error {
+	existing, err := um.client.GetEnforcer(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("enforcer %v already exists", name)
+	}
+	if !strings.Contains(fields.Model, "/") {
+		if _, err = um.findModel(fields.Model); err != nil {
+			return err
+		}
+	}
+	if !strings.Contains(fields.Adapter, "/") {
+		if _, err = um.findAdapter(fields.Adapter); err != nil {
+			return err
+		}
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	_, err = um.client.AddEnforcer(&casdoorsdk.Enforcer{
+		Owner:       um.client.OrganizationName,
+		Name:        name,
+		CreatedTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName: displayName,
+		Description: fields.Description,
+		Model:       um.objectId(fields.Model),
+		Adapter:     um.objectId(fields.Adapter),
+		IsEnabled:   true,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v enforcer has been created successfully", name)
+	return nil
+}
+
+// DeleteEnforcer deletes a Casdoor enforcer. Its policies are kept in the adapter.
+func (um *UserManager) DeleteEnforcer(name string) error {
+	enforcer, err := um.findEnforcer(name)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DeleteEnforcer(enforcer)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v enforcer has been deleted successfully", name)
+	return nil
+}
+
+// GetAdapters returns the Casdoor adapters of the organization. Passwords are never shown.
+func (um *UserManager) GetAdapters() ([]map[string]interface{}, error) {
+	adapters, err := um.client.GetAdapters()
+	if err != nil {
+		return nil, err
+	}
+	var adapterList []map[string]interface{}
+
+	for _, adapter := range adapters {
+		adapterList = append(adapterList, adapterInfo(adapter))
+	}
+	return adapterList, nil
+}
+
+// GetAdapter returns every field of a Casdoor adapter but its password.
+func (um *UserManager) GetAdapter(name string) (map[string]interface{}, error) {
+	adapter, err := um.findAdapter(name)
+	if err != nil {
+		return nil, err
+	}
+	info := adapterInfo(adapter)
+	info["User"] = adapter.User
+	info["TableNamePrefix"] = adapter.TableNamePrefix
+	info["CreatedTime"] = adapter.CreatedTime
+	return info, nil
+}
+
+// AddAdapter creates a Casdoor adapter.
+func (um *UserManager) AddAdapter(name string, fields AdapterFields) error {
+	existing, err := um.client.GetAdapter(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("adapter %v already exists", name)
+	}
+
+	_, err = um.client.AddAdapter(&casdoorsdk.Adapter{
+		Owner:        um.client.OrganizationName,
+		Name:         name,
+		CreatedTime:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Type:         fields.Type,
+		DatabaseType: fields.DatabaseType,
+		Host:         fields.Host,
+		Port:         fields.Port,
+		User:         fields.User,
+		Password:     fields.Password,
+		Database:     fields.Database,
+		Table:        fields.Table,
+		IsEnabled:    true,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v adapter has been created successfully", name)
+	return nil
+}
+
+// DeleteAdapter deletes a Casdoor adapter. An adapter still used by enforcers is only deleted with force.
+func (um *UserManager) DeleteAdapter(name string, force bool) error {
+	adapter, err := um.findAdapter(name)
+	if err != nil {
+		return err
+	}
+
+	enforcers, err := um.client.GetEnforcers()
+	if err != nil {
+		return err
+	}
+	var usedBy []string
+	for _, enforcer := range enforcers {
+		if enforcer.Adapter == um.objectId(name) {
+			usedBy = append(usedBy, enforcer.Name)
+		}
+	}
+	if len(usedBy) > 0 {
+		if !force {
+			return fmt.Errorf("adapter %v is used by the enforcers %v, use --force to delete it anyway", name, strings.Join(usedBy, ", "))
+		}
+		utils.Colorize(color.YellowString, "[⚠] adapter %v is used by the enforcers %v", name, strings.Join(usedBy, ", "))
+	}
+
+	_, err = um.client.DeleteAdapter(adapter)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v adapter has been deleted successfully", name)
+	return nil
+}
+
+func (um *UserManager) findEnforcer(name string) (*casdoorsdk.Enforcer, error) {
+	enforcer, err := um.client.GetEnforcer(name)
+	if err != nil {
+		return nil, err
+	}
+	if enforcer == nil {
+		return nil, fmt.Errorf("enforcer %v doesn't exist", name)
+	}
+	return enforcer, nil
+}
+
+func (um *UserManager) findAdapter(name string) (*casdoorsdk.Adapter, error) {
+	adapter, err := um.client.GetAdapter(name)
+	if err != nil {
+		return nil, err
+	}
+	if adapter == nil {
+		return nil, fmt.Errorf("adapter %v doesn't exist", name)
+	}
+	return adapter, nil
+}
+
+// objectId returns the "<owner>/<name>" id of an object of the organization. Ids of objects
+// owned by another organization are returned as is.
+func (um *UserManager) objectId(name string) string {
+	if name == "" || strings.Contains(name, "/") {
+		return name
+	}
+	return fmt.Sprintf("%s/%s", um.client.OrganizationName, name)
+}
+
+func enforcerInfo(enforcer *casdoorsdk.Enforcer) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":        enforcer.Name,
+		"DisplayName": enforcer.DisplayName,
+		"Model":       enforcer.Model,
+		"Adapter":     enforcer.Adapter,
+		"Enabled":     enforcer.IsEnabled,
+	}
+}
+
+func adapterInfo(adapter *casdoorsdk.Adapter) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":         adapter.Name,
+		"Type":         adapter.Type,
+		"DatabaseType": adapter.DatabaseType,
+		"Host":         adapter.Host,
+		"Port":         adapter.Port,
+		"Database":     adapter.Database,
+		"Table":        adapter.Table,
+		"Enabled":      adapter.IsEnabled,
+	}
+}
@@ -0,0 +1,239 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GroupDeletionPlan describes what deleting a group affects: the users referencing it and
+// its child groups.
+type GroupDeletionPlan struct {
+	Group    *casdoorsdk.Group
+	Members  []*casdoorsdk.User
+	Children []*casdoorsdk.Group
+
+	groupsByName map[string]*casdoorsdk.Group
+}
+
+// groupDeletionRollback is the content of the rollback file written before a group deletion.
+// It holds the previous state of everything the deletion changes.
+type groupDeletionRollback struct {
+	CreatedTime  string                `json:"createdTime"`
+	Group        *casdoorsdk.Group     `json:"group"`
+	ReassignedTo string                `json:"reassignedTo"`
+	Users        []rollbackUserGroups  `json:"users"`
+	Children     []rollbackGroupParent `json:"children"`
+}
+
+type rollbackUserGroups struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups"`
+}
+
+type rollbackGroupParent struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent"`
+}
+
+// PlanGroupDeletion looks up the members and child groups of the group to delete.
+func (um *UserManager) PlanGroupDeletion(name string) (*GroupDeletionPlan, error) {
+	groups, err := um.client.GetGroups()
+	if err != nil {
+		return nil, err
+	}
+	plan := &GroupDeletionPlan{groupsByName: map[string]*casdoorsdk.Group{}}
+	for _, group := range groups {
+		plan.groupsByName[group.Name] = group
+		if group.Name == name {
+			plan.Group = group
+		}
+	}
+	if plan.Group == nil {
+		return nil, fmt.Errorf("group %v doesn't exist", name)
+	}
+
+	for _, group := range groups {
+		if um.parentName(group) == name {
+			plan.Children = append(plan.Children, group)
+		}
+	}
+
+	users, err := um.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if containsString(groupNames(user.Groups), name) {
+			plan.Members = append(plan.Members, user)
+		}
+	}
+	return plan, nil
+}
+
+// IsEmpty returns true if no user nor child group references the group.
+func (p *GroupDeletionPlan) IsEmpty() bool {
+	return len(p.Members) == 0 && len(p.Children) == 0
+}
+
+// AffectedList returns the users and child groups affected by the deletion.
+func (p *GroupDeletionPlan) AffectedList() []map[string]interface{} {
+	var affectedList []map[string]interface{}
+
+	for _, user := range p.Members {
+		affectedList = append(affectedList, map[string]interface{}{
+			"Kind":    "user",
+			"Name":    user.Name,
+			"Details": "groups: " + strings.Join(groupNames(user.Groups), ", "),
+		})
+	}
+	for _, group := range p.Children {
+		affectedList = append(affectedList, map[string]interface{}{
+			"Kind":    "child group",
+			"Name":    group.Name,
+			"Details": "parent: " + p.Group.Name,
+		})
+	}
+	return affectedList
+}
+
+// ExecuteGroupDeletion deletes the planned group. When reassignTo is set, members are moved to that
+// group and child groups are moved under it. Otherwise the group is removed from its members and
+// child groups become top groups, so that no dangling reference is left. The previous state is
+// written to rollbackPath before anything is changed.
+func (um *UserManager) ExecuteGroupDeletion(plan *GroupDeletionPlan, reassignTo string, rollbackPath string) error {
+	name := plan.Group.Name
+	if reassignTo != "" {
+		if reassignTo == name {
+			return fmt.Errorf("cannot reassign %v to itself", name)
+		}
+		for _, child := range plan.Children {
+			if err := checkGroupParent(plan.groupsByName, child.Name, reassignTo); err != nil {
+				return err
+			}
+		}
+		if _, ok := plan.groupsByName[reassignTo]; !ok {
+			return fmt.Errorf("group %v doesn't exist", reassignTo)
+		}
+	}
+
+	rollback := groupDeletionRollback{
+		CreatedTime:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Group:        plan.Group,
+		ReassignedTo: reassignTo,
+		Users:        []rollbackUserGroups{},
+		Children:     []rollbackGroupParent{},
+	}
+	for _, user := range plan.Members {
+		rollback.Users = append(rollback.Users, rollbackUserGroups{Name: user.Name, Groups: groupNames(user.Groups)})
+	}
+	for _, child := range plan.Children {
+		rollback.Children = append(rollback.Children, rollbackGroupParent{Name: child.Name, Parent: name})
+	}
+	if !plan.IsEmpty() {
+		if err := writeRollbackFile(rollbackPath, rollback); err != nil {
+			return err
+		}
+		utils.Colorize(color.CyanString, "[ℹ] rollback file written to %v", rollbackPath)
+	}
+
+	for _, user := range plan.Members {
+		var userGroups []string
+		for _, group := range groupNames(user.Groups) {
+			if group == name {
+				group = reassignTo
+			}
+			if group != "" && !containsString(userGroups, group) {
+				userGroups = append(userGroups, group)
+			}
+		}
+		if err := um.updateUserGroups(user, userGroups); err != nil {
+			return fmt.Errorf("updating user %v failed, use the rollback file to restore: %v", user.Name, err)
+		}
+	}
+
+	for _, child := range plan.Children {
+		um.applyGroupFields(child, GroupFields{
+			DisplayName: child.DisplayName,
+			Type:        child.Type,
+			Parent:      reassignTo,
+			Manager:     child.Manager,
+		})
+		if err := um.updateGroupById(fmt.Sprintf("%s/%s", child.Owner, child.Name), child); err != nil {
+			return fmt.Errorf("updating group %v failed, use the rollback file to restore: %v", child.Name, err)
+		}
+	}
+
+	_, err := um.client.DeleteGroup(&casdoorsdk.Group{Name: name, Owner: plan.Group.Owner})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v group has been deleted successfully (%d members and %d child groups updated)", name, len(plan.Members), len(plan.Children))
+	return nil
+}
+
+// RollbackGroupDeletion restores a deleted group, its memberships and its child groups from a
+// rollback file written by ExecuteGroupDeletion.
+func (um *UserManager) RollbackGroupDeletion(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var rollback groupDeletionRollback
+	err = json.Unmarshal(data, &rollback)
+	if err != nil {
+		return err
+	}
+	if rollback.Group == nil {
+		return fmt.Errorf("%v is not a group deletion rollback file", path)
+	}
+
+	existing, err := um.client.GetGroup(rollback.Group.Name)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		_, err = um.client.AddGroup(rollback.Group)
+		if err != nil {
+			return err
+		}
+		utils.Colorize(color.GreenString, "[✔] %v group has been restored", rollback.Group.Name)
+	}
+
+	for _, previous := range rollback.Users {
+		user, err := um.findUser(previous.Name)
+		if err != nil {
+			return err
+		}
+		if err = um.updateUserGroups(user, previous.Groups); err != nil {
+			return err
+		}
+	}
+
+	for _, previous := range rollback.Children {
+		parent := previous.Parent
+		if err = um.UpdateGroup(previous.Name, GroupUpdate{Parent: &parent}); err != nil {
+			return err
+		}
+	}
+	utils.Colorize(color.GreenString, "[✔] %d memberships and %d child groups have been restored", len(rollback.Users), len(rollback.Children))
+	return nil
+}
+
+func writeRollbackFile(path string, rollback groupDeletionRollback) error {
+	data, err := json.MarshalIndent(rollback, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
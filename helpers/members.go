@@ -0,0 +1,202 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+)
+
+// GetGroupMembers returns every user of the organization that belongs to the given group.
+func (um *UserManager) GetGroupMembers(groupName string) ([]map[string]interface{}, error) {
+	if _, err := um.findGroup(groupName); err != nil {
+		return nil, err
+	}
+
+	users, err := um.client.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+	var memberList []map[string]interface{}
+
+	for _, user := range users {
+		if !containsString(groupNames(user.Groups), groupName) {
+			continue
+		}
+		memberInfo := map[string]interface{}{
+			"Name":   user.Name,
+			"Email":  user.Email,
+			"Id":     user.Id,
+			"Groups": strings.Join(groupNames(user.Groups), ", "),
+		}
+
+		memberList = append(memberList, memberInfo)
+	}
+	return memberList, nil
+}
+
+// AddUserToGroups adds the user to each of the given groups, keeping its current memberships.
+func (um *UserManager) AddUserToGroups(userName string, groups []string) error {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return err
+	}
+	if err = um.checkGroupsExist(groups); err != nil {
+		return err
+	}
+
+	current := groupNames(user.Groups)
+	for _, group := range groups {
+		if !containsString(current, group) {
+			current = append(current, group)
+		}
+	}
+
+	err = um.updateUserGroups(user, current)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v now belongs to %v", userName, strings.Join(current, ", "))
+	return nil
+}
+
+// RemoveUserFromGroups removes the user from each of the given groups, keeping its other memberships.
+func (um *UserManager) RemoveUserFromGroups(userName string, groups []string) error {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return err
+	}
+
+	var remaining []string
+	for _, group := range groupNames(user.Groups) {
+		if !containsString(groups, group) {
+			remaining = append(remaining, group)
+		}
+	}
+
+	err = um.updateUserGroups(user, remaining)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v has been removed from %v", userName, strings.Join(groups, ", "))
+	return nil
+}
+
+// SetUserGroups replaces all the memberships of the user with the given groups.
+func (um *UserManager) SetUserGroups(userName string, groups []string) error {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return err
+	}
+	if err = um.checkGroupsExist(groups); err != nil {
+		return err
+	}
+
+	err = um.updateUserGroups(user, groups)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v now belongs to %v", userName, strings.Join(groups, ", "))
+	return nil
+}
+
+// AddGroupMembers adds each of the given users to the group.
+func (um *UserManager) AddGroupMembers(groupName string, userNames []string) error {
+	for _, userName := range userNames {
+		err := um.AddUserToGroups(userName, []string{groupName})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveGroupMembers removes each of the given users from the group.
+func (um *UserManager) RemoveGroupMembers(groupName string, userNames []string) error {
+	for _, userName := range userNames {
+		err := um.RemoveUserFromGroups(userName, []string{groupName})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateUserGroups only writes the groups column so that every other field of the user stays untouched.
+func (um *UserManager) updateUserGroups(user *casdoorsdk.User, groups []string) error {
+	user.Groups = um.groupIds(groups)
+	_, err := um.client.UpdateUserForColumns(user, []string{"groups"})
+	return err
+}
+
+func (um *UserManager) findUser(name string) (*casdoorsdk.User, error) {
+	user, err := um.client.GetUser(name)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %v doesn't exist", name)
+	}
+	return user, nil
+}
+
+func (um *UserManager) findGroup(name string) (*casdoorsdk.Group, error) {
+	group, err := um.client.GetGroup(name)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("group %v doesn't exist", name)
+	}
+	return group, nil
+}
+
+func (um *UserManager) checkGroupsExist(names []string) error {
+	for _, name := range names {
+		if _, err := um.findGroup(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupIds converts group names to the "<owner>/<name>" identifiers stored in a user's groups.
+// Roles reference their users and sub-roles with the same identifiers.
+func (um *UserManager) groupIds(names []string) []string {
+	ids := []string{}
+	for _, name := range names {
+		ids = append(ids, fmt.Sprintf("%s/%s", um.client.OrganizationName, name))
+	}
+	return ids
+}
+
+// groupNames strips the owner prefix from the group identifiers stored in a user's groups, or
+// from the user and sub-role identifiers of a role.
+func groupNames(ids []string) []string {
+	var names []string
+	for _, id := range ids {
+		names = append(names, id[strings.LastIndex(id, "/")+1:])
+	}
+	return names
+}
+
+// splitList splits a comma separated input into its trimmed, non-empty items.
+func splitList(input string) []string {
+	var items []string
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// getRawObject returns a Casdoor object as raw JSON, such as get-organization for the id
+// admin/my-org. Casdoor updates every column of organizations or applications, so they are
+// updated from their raw JSON in order to keep the fields the SDK doesn't know about, such as the
+// logo of an organization or the sign-in methods of an application.
+func (um *UserManager) getRawObject(action string, id string) (map[string]interface{}, error) {
+	url := um.client.GetUrl(action, map[string]string{"id": id})
+	data, err := um.client.DoGetBytes(url)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]interface{}
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, notFoundError{id}
+	}
+	return raw, nil
+}
+
+// notFoundError is returned by getRawObject when Casdoor has no object with the id, as opposed to
+// a failed request.
+type notFoundError struct {
+	id string
+}
+
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("%v doesn't exist", e.id)
+}
+
+// updateRawObject posts an object returned by getRawObject to an update action, such as update-organization.
+func (um *UserManager) updateRawObject(action string, id string, raw map[string]interface{}) error {
+	postBytes, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DoPost(action, map[string]string{"id": id}, postBytes, false, false)
+	return err
+}
+
+// rawStrings returns a list of strings of a raw object, such as the redirect URIs of an application.
+func rawStrings(raw map[string]interface{}, key string) []string {
+	items, _ := raw[key].([]interface{})
+	values := []string{}
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+)
+
+// OffboardUser locks the account of a leaving user without deleting it. The profile is exported
+// first when exportPath is set, then the account is disabled, its tokens and sessions are revoked
+// and its group memberships are removed. Every step is attempted and reported, even if a previous
+// one failed.
+func (um *UserManager) OffboardUser(name string, exportPath string) (map[string]interface{}, error) {
+	user, err := um.findUser(name)
+	if err != nil {
+		return nil, err
+	}
+
+	report := map[string]interface{}{
+		"User": name,
+	}
+	failed := false
+	record := func(step string, result string, err error) {
+		if err != nil {
+			failed = true
+			result = fmt.Sprintf("failed: %v", err)
+		}
+		report[step] = result
+	}
+
+	if exportPath != "" {
+		record("Export", exportPath, exportUserProfile(user, exportPath))
+	} else {
+		record("Export", "skipped", nil)
+	}
+
+	user.IsForbidden = true
+	_, err = um.client.UpdateUserForColumns(user, []string{"is_forbidden"})
+	record("Disabled", "yes", err)
+
+	tokens, err := um.revokeUserTokens(name)
+	record("Tokens revoked", fmt.Sprintf("%d", tokens), err)
+
+	sessions, err := um.revokeUserSessions(name)
+	record("Sessions revoked", fmt.Sprintf("%d", sessions), err)
+
+	groups := groupNames(user.Groups)
+	record("Groups removed", strings.Join(groups, ", "), um.updateUserGroups(user, nil))
+
+	if failed {
+		return report, errors.New("offboarding is incomplete, see the report above")
+	}
+	utils.Colorize(color.GreenString, "[✔] %v has been offboarded successfully", name)
+	return report, nil
+}
+
+// revokeUserTokens deletes every token issued to the user and returns how many were deleted.
+func (um *UserManager) revokeUserTokens(name string) (int, error) {
+	tokens, err := um.client.GetTokens()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, token := range tokens {
+		if token.User != name || token.Organization != um.client.OrganizationName {
+			continue
+		}
+		_, err = um.client.DeleteToken(token)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// revokeUserSessions deletes every session of the user and returns how many were deleted.
+func (um *UserManager) revokeUserSessions(name string) (int, error) {
+	sessions, err := um.client.GetSessions()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, session := range sessions {
+		if session.Name != name {
+			continue
+		}
+		_, err = um.client.DeleteSession(session)
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// exportUserProfile writes the full user profile as JSON. The file is only readable by its owner
+// as the profile holds the password hash and other personal data.
+func exportUserProfile(user *casdoorsdk.User, path string) error {
+	data, err := json.MarshalIndent(user, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
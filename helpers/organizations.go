@@ -0,0 +1,341 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+	"time"
+)
+
+// Values accepted by Casdoor for the organization settings.
+var (
+	PasswordTypes    = []string{"plain", "salt", "sha512-salt", "md5-salt", "bcrypt", "pbkdf2-salt", "argon2id"}
+	PasswordOptions  = []string{"AtLeast6", "AtLeast8", "Aa123", "SpecialChar", "NoRepeat"}
+	MfaRules         = []string{"Optional", "Prompted", "Required"}
+	AccountViewRules = []string{"Public", "Self", "Admin"}
+	AccountEditRules = []string{"Self", "Admin", "Immutable"}
+)
+
+// OrganizationFields holds the fields of a new organization besides its name.
+type OrganizationFields struct {
+	DisplayName        string
+	WebsiteUrl         string
+	PasswordType       string
+	PasswordOptions    []string
+	Languages          []string
+	DefaultApplication string
+}
+
+// OrganizationUpdate holds the fields to change on an organization. Nil fields are preserved.
+// MfaItems maps MFA item names such as "email" or "app" to their rule.
+type OrganizationUpdate struct {
+	DisplayName        *string
+	WebsiteUrl         *string
+	PasswordType       *string
+	PasswordOptions    *[]string
+	Languages          *[]string
+	DefaultApplication *string
+	MfaItems           map[string]string
+}
+
+// AccountItemUpdate holds the visibility rules to change on an account item. Nil fields are preserved.
+type AccountItemUpdate struct {
+	Visible    *bool
+	ViewRule   *string
+	ModifyRule *string
+}
+
+// GetOrganizations returns the organizations visible to the CLI application.
+func (um *UserManager) GetOrganizations() ([]map[string]interface{}, error) {
+	organizations, err := um.client.GetOrganizations()
+	if err != nil {
+		return nil, err
+	}
+	var organizationList []map[string]interface{}
+
+	for _, organization := range organizations {
+		organizationList = append(organizationList, map[string]interface{}{
+			"Name":               organization.Name,
+			"DisplayName":        organization.DisplayName,
+			"PasswordType":       organization.PasswordType,
+			"DefaultApplication": organization.DefaultApplication,
+			"CreatedTime":        organization.CreatedTime,
+		})
+	}
+	return organizationList, nil
+}
+
+// GetOrganization returns the settings of an organization.
+func (um *UserManager) GetOrganization(name string) (map[string]interface{}, error) {
+	organization, err := um.findOrganization(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var mfaItems []string
+	for _, item := range organization.MfaItems {
+		mfaItems = append(mfaItems, fmt.Sprintf("%s=%s", item.Name, item.Rule))
+	}
+	return map[string]interface{}{
+		"Name":               organization.Name,
+		"DisplayName":        organization.DisplayName,
+		"WebsiteUrl":         organization.WebsiteUrl,
+		"PasswordType":       organization.PasswordType,
+		"PasswordOptions":    strings.Join(organization.PasswordOptions, ", "),
+		"MfaItems":           strings.Join(mfaItems, ", "),
+		"Languages":          strings.Join(organization.Languages, ", "),
+		"DefaultApplication": organization.DefaultApplication,
+	}, nil
+}
+
+// GetAccountItems returns the account items of an organization along with their visibility rules.
+func (um *UserManager) GetAccountItems(name string) ([]map[string]interface{}, error) {
+	organization, err := um.findOrganization(name)
+	if err != nil {
+		return nil, err
+	}
+	var itemList []map[string]interface{}
+
+	for _, item := range organization.AccountItems {
+		itemList = append(itemList, map[string]interface{}{
+			"Name":       item.Name,
+			"Visible":    item.Visible,
+			"ViewRule":   item.ViewRule,
+			"ModifyRule": item.ModifyRule,
+		})
+	}
+	return itemList, nil
+}
+
+// AddOrganization creates an organization. Its MFA and account items are copied from the
+// organization of the CLI, so that the new organization is usable right away.
+func (um *UserManager) AddOrganization(name string, fields OrganizationFields) error {
+	existing, err := um.client.GetOrganization(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("organization %v already exists", name)
+	}
+	if err = checkOrganizationSettings(&fields.PasswordType, &fields.PasswordOptions); err != nil {
+		return err
+	}
+	template, err := um.findOrganization(um.client.OrganizationName)
+	if err != nil {
+		return err
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	_, err = um.client.AddOrganization(&casdoorsdk.Organization{
+		Owner:              "admin",
+		Name:               name,
+		CreatedTime:        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName:        displayName,
+		WebsiteUrl:         fields.WebsiteUrl,
+		PasswordType:       fields.PasswordType,
+		PasswordOptions:    nonNilList(fields.PasswordOptions),
+		CountryCodes:       template.CountryCodes,
+		DefaultAvatar:      template.DefaultAvatar,
+		DefaultApplication: fields.DefaultApplication,
+		Tags:               []string{},
+		Languages:          nonNilList(fields.Languages),
+		MfaItems:           template.MfaItems,
+		AccountItems:       template.AccountItems,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v organization has been created successfully", name)
+	return nil
+}
+
+// UpdateOrganization changes the given settings of an organization and preserves the other ones.
+func (um *UserManager) UpdateOrganization(name string, update OrganizationUpdate) error {
+	organization, raw, err := um.getRawOrganization(name)
+	if err != nil {
+		return err
+	}
+
+	passwordType := organization.PasswordType
+	if update.PasswordType != nil {
+		passwordType = *update.PasswordType
+	}
+	passwordOptions := organization.PasswordOptions
+	if update.PasswordOptions != nil {
+		passwordOptions = *update.PasswordOptions
+	}
+	if err = checkOrganizationSettings(&passwordType, &passwordOptions); err != nil {
+		return err
+	}
+
+	changed := false
+	setRaw := func(key string, value interface{}) {
+		raw[key] = value
+		changed = true
+	}
+	if update.DisplayName != nil {
+		setRaw("displayName", *update.DisplayName)
+	}
+	if update.WebsiteUrl != nil {
+		setRaw("websiteUrl", *update.WebsiteUrl)
+	}
+	if update.PasswordType != nil {
+		setRaw("passwordType", passwordType)
+	}
+	if update.PasswordOptions != nil {
+		setRaw("passwordOptions", nonNilList(passwordOptions))
+	}
+	if update.Languages != nil {
+		setRaw("languages", nonNilList(*update.Languages))
+	}
+	if update.DefaultApplication != nil {
+		if *update.DefaultApplication != "" {
+			if _, err = um.findApplication(*update.DefaultApplication); err != nil {
+				return err
+			}
+		}
+		setRaw("defaultApplication", *update.DefaultApplication)
+	}
+	if len(update.MfaItems) > 0 {
+		mfaItems := organization.MfaItems
+		for itemName, rule := range update.MfaItems {
+			if !containsString(MfaRules, rule) {
+				return fmt.Errorf("invalid MFA rule %v for %v (expected one of %v)", rule, itemName, strings.Join(MfaRules, ", "))
+			}
+			found := false
+			for _, item := range mfaItems {
+				if item.Name == itemName {
+					item.Rule = rule
+					found = true
+				}
+			}
+			if !found {
+				mfaItems = append(mfaItems, &casdoorsdk.MfaItem{Name: itemName, Rule: rule})
+			}
+		}
+		setRaw("mfaItems", mfaItems)
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update on organization %v", name)
+	}
+
+	err = um.updateRawOrganization(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v organization has been updated successfully", name)
+	return nil
+}
+
+// UpdateAccountItem changes the visibility rules of an account item, such as "Phone", of an organization.
+func (um *UserManager) UpdateAccountItem(name string, itemName string, update AccountItemUpdate) error {
+	if update.Visible == nil && update.ViewRule == nil && update.ModifyRule == nil {
+		return fmt.Errorf("nothing to update on account item %v", itemName)
+	}
+	if update.ViewRule != nil && !containsString(AccountViewRules, *update.ViewRule) {
+		return fmt.Errorf("invalid view rule %v (expected one of %v)", *update.ViewRule, strings.Join(AccountViewRules, ", "))
+	}
+	if update.ModifyRule != nil && !containsString(AccountEditRules, *update.ModifyRule) {
+		return fmt.Errorf("invalid modify rule %v (expected one of %v)", *update.ModifyRule, strings.Join(AccountEditRules, ", "))
+	}
+	_, raw, err := um.getRawOrganization(name)
+	if err != nil {
+		return err
+	}
+
+	// account items are changed on the raw JSON, so that fields unknown to the SDK such as regex are kept
+	items, _ := raw["accountItems"].([]interface{})
+	found := false
+	for _, rawItem := range items {
+		item, ok := rawItem.(map[string]interface{})
+		if !ok || item["name"] != itemName {
+			continue
+		}
+		found = true
+		if update.Visible != nil {
+			item["visible"] = *update.Visible
+		}
+		if update.ViewRule != nil {
+			item["viewRule"] = *update.ViewRule
+		}
+		if update.ModifyRule != nil {
+			item["modifyRule"] = *update.ModifyRule
+		}
+	}
+	if !found {
+		return fmt.Errorf("account item %v doesn't exist in organization %v", itemName, name)
+	}
+
+	err = um.updateRawOrganization(name, raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v account item of %v has been updated successfully", itemName, name)
+	return nil
+}
+
+// DeleteOrganization deletes an organization. The organization of the CLI itself cannot be deleted.
+func (um *UserManager) DeleteOrganization(name string, cliOrganization string) error {
+	if name == cliOrganization {
+		return fmt.Errorf("organization %v is the organization of the CLI and cannot be deleted", name)
+	}
+	organization, err := um.findOrganization(name)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DeleteOrganization(organization)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v organization has been deleted successfully", name)
+	return nil
+}
+
+func (um *UserManager) findOrganization(name string) (*casdoorsdk.Organization, error) {
+	organization, err := um.client.GetOrganization(name)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil {
+		return nil, fmt.Errorf("organization %v doesn't exist", name)
+	}
+	return organization, nil
+}
+
+// getRawOrganization returns an organization both as parsed by the SDK and as raw JSON.
+func (um *UserManager) getRawOrganization(name string) (*casdoorsdk.Organization, map[string]interface{}, error) {
+	organization, err := um.findOrganization(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := um.getRawObject("get-organization", fmt.Sprintf("admin/%s", name))
+	if err != nil {
+		return nil, nil, err
+	}
+	return organization, raw, nil
+}
+
+func (um *UserManager) updateRawOrganization(name string, raw map[string]interface{}) error {
+	return um.updateRawObject("update-organization", fmt.Sprintf("admin/%s", name), raw)
+}
+
+// checkOrganizationSettings checks the password type and options, defaulting to a plain password type.
+func checkOrganizationSettings(passwordType *string, passwordOptions *[]string) error {
+	if *passwordType == "" {
+		*passwordType = "plain"
+	}
+	if !containsString(PasswordTypes, *passwordType) {
+		return fmt.Errorf("invalid password type %v (expected one of %v)", *passwordType, strings.Join(PasswordTypes, ", "))
+	}
+	for _, option := range *passwordOptions {
+		if !containsString(PasswordOptions, option) {
+			return fmt.Errorf("invalid password option %v (expected one of %v)", option, strings.Join(PasswordOptions, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"math/big"
+	"strings"
+)
+
+const (
+	lowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+	specialChars = "!@#$%^&*" // the ones the SpecialChar option of Casdoor accepts
+)
+
+// wrongPasswordMessage starts the error Casdoor returns for a wrong password, such as
+// "password or code is incorrect, you have 4 remaining chances".
+const wrongPasswordMessage = "password or code is incorrect"
+
+// defaultPasswordOptions is used when the organization doesn't define any password option.
+var defaultPasswordOptions = []string{"AtLeast6"}
+
+// SetUserPassword sets a new password for the user. Being authenticated as the application,
+// the old password isn't required.
+func (um *UserManager) SetUserPassword(name string, password string) error {
+	user, err := um.findUser(name)
+	if err != nil {
+		return err
+	}
+
+	options, err := um.GetPasswordOptions()
+	if err != nil {
+		return err
+	}
+	if err = CheckPasswordOptions(password, options); err != nil {
+		return err
+	}
+
+	_, err = um.client.SetPassword(user.Owner, user.Name, "", password)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] password of %v has been set successfully", name)
+	return nil
+}
+
+// CheckUserPassword returns true if the password matches the one of the user. Casdoor checks it
+// the way it checks a sign-in, so a wrong password counts toward the sign-in lockout of the user.
+func (um *UserManager) CheckUserPassword(name string, password string) (bool, error) {
+	user, err := um.findUser(name)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = um.client.CheckUserPassword(&casdoorsdk.User{
+		Owner:    user.Owner,
+		Name:     user.Name,
+		Password: password,
+	})
+	if err != nil {
+		// a wrong password is reported by Casdoor as an error, anything else is a real failure
+		if strings.HasPrefix(err.Error(), wrongPasswordMessage) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetPasswordOptions returns the password options of the organization such as
+// "AtLeast6", "AtLeast8", "Aa123", "SpecialChar" and "NoRepeat".
+func (um *UserManager) GetPasswordOptions() ([]string, error) {
+	organization, err := um.client.GetOrganization(um.client.OrganizationName)
+	if err != nil {
+		return nil, err
+	}
+	if organization == nil || len(organization.PasswordOptions) == 0 {
+		return defaultPasswordOptions, nil
+	}
+	return organization.PasswordOptions, nil
+}
+
+// CheckPasswordOptions validates the password against the organization password options
+// the same way Casdoor does.
+func CheckPasswordOptions(password string, options []string) error {
+	for _, option := range options {
+		switch option {
+		case "AtLeast6":
+			if len(password) < 6 {
+				return errors.New("password must have at least 6 characters")
+			}
+		case "AtLeast8":
+			if len(password) < 8 {
+				return errors.New("password must have at least 8 characters")
+			}
+		case "Aa123":
+			if !strings.ContainsAny(password, upperChars) ||
+				!strings.ContainsAny(password, lowerChars) ||
+				!strings.ContainsAny(password, digitChars) {
+				return errors.New("password must contain at least one uppercase letter, one lowercase letter and one digit")
+			}
+		case "SpecialChar":
+			if !strings.ContainsAny(password, specialChars) {
+				return errors.New("password must contain at least one special character")
+			}
+		case "NoRepeat":
+			for i := 1; i < len(password); i++ {
+				if password[i] == password[i-1] {
+					return errors.New("password must not contain repeated characters")
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// GeneratePassword returns a random password of the given length that satisfies the password
+// options. The length is raised to the minimum required by the options when needed.
+func GeneratePassword(options []string, length int) (string, error) {
+	charsets := []string{lowerChars, upperChars, digitChars}
+	for _, option := range options {
+		switch option {
+		case "AtLeast6":
+			length = max(length, 6)
+		case "AtLeast8":
+			length = max(length, 8)
+		case "SpecialChar":
+			charsets = append(charsets, specialChars)
+		}
+	}
+	length = max(length, len(charsets))
+
+	// one character of every charset is guaranteed, the rest is drawn from all of them
+	password := make([]byte, 0, length)
+	for _, charset := range charsets {
+		c, err := randomChar(charset)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	all := strings.Join(charsets, "")
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	// repeated characters are replaced until none is left, for the "NoRepeat" option
+	for i := 1; i < len(password); i++ {
+		for password[i] == password[i-1] {
+			c, err := randomChar(charsetOf(password[i]))
+			if err != nil {
+				return "", err
+			}
+			password[i] = c
+		}
+	}
+
+	generated := string(password)
+	if err := CheckPasswordOptions(generated, options); err != nil {
+		return "", fmt.Errorf("generated password doesn't satisfy the password options: %v", err)
+	}
+	return generated, nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, err
+	}
+	return charset[n.Int64()], nil
+}
+
+func charsetOf(c byte) string {
+	for _, charset := range []string{lowerChars, upperChars, digitChars} {
+		if strings.IndexByte(charset, c) >= 0 {
+			return charset
+		}
+	}
+	return specialChars
+}
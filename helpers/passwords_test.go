@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPasswordOptions(t *testing.T) {
+	tests := []struct {
+		password string
+		options  []string
+		wantErr  bool
+	}{
+		{"abcde", []string{"AtLeast6"}, true},
+		{"abcdef", []string{"AtLeast6"}, false},
+		{"abcdefg", []string{"AtLeast8"}, true},
+		{"abcdefgh", []string{"AtLeast8"}, false},
+		{"abcdef1", []string{"Aa123"}, true},
+		{"Abcdef1", []string{"Aa123"}, false},
+		{"Abcdef1", []string{"SpecialChar"}, true},
+		{"Abcdef1!", []string{"SpecialChar"}, false},
+		{"Abccdef", []string{"NoRepeat"}, true},
+		{"Abcdef", []string{"NoRepeat"}, false},
+		{"Abcdef1!", []string{"AtLeast8", "Aa123", "SpecialChar", "NoRepeat"}, false},
+		{"x", nil, false},
+	}
+
+	for _, tt := range tests {
+		err := CheckPasswordOptions(tt.password, tt.options)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckPasswordOptions(%q, %v) error = %v, wantErr %v", tt.password, tt.options, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		options    []string
+		length     int
+		wantLength int
+	}{
+		{[]string{"AtLeast6"}, 0, 6},
+		{[]string{"AtLeast8"}, 4, 8},
+		{[]string{"AtLeast8"}, 20, 20},
+		{[]string{"Aa123", "SpecialChar", "NoRepeat"}, 0, 4},
+		{[]string{"AtLeast8", "Aa123", "SpecialChar", "NoRepeat"}, 16, 16},
+		{nil, 12, 12},
+	}
+
+	for _, tt := range tests {
+		// the password is random, so each case is generated several times
+		for i := 0; i < 50; i++ {
+			password, err := GeneratePassword(tt.options, tt.length)
+			if err != nil {
+				t.Fatalf("GeneratePassword(%v, %d) error = %v", tt.options, tt.length, err)
+			}
+			if len(password) != tt.wantLength {
+				t.Errorf("GeneratePassword(%v, %d) = %q, want %d characters", tt.options, tt.length, password, tt.wantLength)
+			}
+			if err = CheckPasswordOptions(password, append(tt.options, "Aa123", "NoRepeat")); err != nil {
+				t.Errorf("GeneratePassword(%v, %d) = %q: %v", tt.options, tt.length, password, err)
+			}
+			if strings.Trim(password, lowerChars+upperChars+digitChars+specialChars) != "" {
+				t.Errorf("GeneratePassword(%v, %d) = %q contains unexpected characters", tt.options, tt.length, password)
+			}
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strings"
+	"time"
+)
+
+// Approval states of a Casdoor permission.
+const (
+	PermissionPending  = "Pending"
+	PermissionApproved = "Approved"
+	PermissionRejected = "Rejected"
+)
+
+// PermissionFields holds the fields of a Casdoor permission besides its name. Users, Groups and
+// Roles are names within the organization, "*" standing for all of them.
+type PermissionFields struct {
+	DisplayName  string
+	Description  string
+	Users        []string
+	Groups       []string
+	Roles        []string
+	Domains      []string
+	Model        string
+	Adapter      string
+	ResourceType string
+	Resources    []string
+	Actions      []string
+	Effect       string
+	Enabled      bool
+	State        string
+}
+
+// PermissionUpdate holds the fields to change on a permission. Nil fields are preserved.
+type PermissionUpdate struct {
+	DisplayName  *string
+	Description  *string
+	Users        *[]string
+	Groups       *[]string
+	Roles        *[]string
+	Domains      *[]string
+	Model        *string
+	Adapter      *string
+	ResourceType *string
+	Resources    *[]string
+	Actions      *[]string
+	Effect       *string
+	Enabled      *bool
+	State        *string
+}
+
+// GetPermissions returns the Casdoor permissions of the organization. When role is set, only the
+// permissions granted to that role are returned, and when state is set, only the permissions in
+// that approval state.
+func (um *UserManager) GetPermissions(role string, state string) ([]map[string]interface{}, error) {
+	var permissions []*casdoorsdk.Permission
+	var err error
+	if role != "" {
+		if _, err = um.findRole(role); err != nil {
+			return nil, err
+		}
+		permissions, err = um.client.GetPermissionsByRole(role)
+	} else {
+		permissions, err = um.client.GetPermissions()
+	}
+	if err != nil {
+		return nil, err
+	}
+	var permissionList []map[string]interface{}
+
+	for _, permission := range permissions {
+		if state != "" && !strings.EqualFold(permission.State, state) {
+			continue
+		}
+		permissionList = append(permissionList, permissionInfo(permission))
+	}
+	return permissionList, nil
+}
+
+// GetPermission returns every field of a Casdoor permission.
+func (um *UserManager) GetPermission(name string) (map[string]interface{}, error) {
+	permission, err := um.findPermission(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := permissionInfo(permission)
+	info["Description"] = permission.Description
+	info["Groups"] = strings.Join(groupNames(permission.Groups), ", ")
+	info["Domains"] = strings.Join(permission.Domains, ", ")
+	info["Model"] = permission.Model
+	info["Adapter"] = permission.Adapter
+	info["Submitter"] = permission.Submitter
+	info["Approver"] = permission.Approver
+	info["ApproveTime"] = permission.ApproveTime
+	return info, nil
+}
+
+// AddPermission creates a Casdoor permission submitted by the given user.
+func (um *UserManager) AddPermission(name string, fields PermissionFields, submitter string) error {
+	existing, err := um.client.GetPermission(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("permission %v already exists", name)
+	}
+	if err = checkPermissionFields(fields.Effect, fields.State); err != nil {
+		return err
+	}
+	if err = um.checkRolesExist(fields.Roles); err != nil {
+		return err
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	permission := casdoorsdk.Permission{
+		Owner:        um.client.OrganizationName,
+		Name:         name,
+		CreatedTime:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName:  displayName,
+		Description:  fields.Description,
+		Users:        um.groupIds(fields.Users),
+		Groups:       um.groupIds(fields.Groups),
+		Roles:        um.groupIds(fields.Roles),
+		Domains:      nonNilList(fields.Domains),
+		Model:        fields.Model,
+		Adapter:      fields.Adapter,
+		ResourceType: fields.ResourceType,
+		Resources:    nonNilList(fields.Resources),
+		Actions:      nonNilList(fields.Actions),
+		Effect:       fields.Effect,
+		IsEnabled:    fields.Enabled,
+		Submitter:    submitter,
+		State:        fields.State,
+	}
+	_, err = um.client.AddPermission(&permission)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v permission has been created successfully", name)
+	return nil
+}
+
+// UpdatePermission changes the given fields of a permission and preserves the other ones.
+func (um *UserManager) UpdatePermission(name string, update PermissionUpdate) error {
+	permission, err := um.findPermission(name)
+	if err != nil {
+		return err
+	}
+
+	var columns []string
+	if update.DisplayName != nil {
+		permission.DisplayName = *update.DisplayName
+		columns = append(columns, "display_name")
+	}
+	if update.Description != nil {
+		permission.Description = *update.Description
+		columns = append(columns, "description")
+	}
+	if update.Users != nil {
+		permission.Users = um.groupIds(*update.Users)
+		columns = append(columns, "users")
+	}
+	if update.Groups != nil {
+		permission.Groups = um.groupIds(*update.Groups)
+		columns = append(columns, "groups")
+	}
+	if update.Roles != nil {
+		if err = um.checkRolesExist(*update.Roles); err != nil {
+			return err
+		}
+		permission.Roles = um.groupIds(*update.Roles)
+		columns = append(columns, "roles")
+	}
+	if update.Domains != nil {
+		permission.Domains = nonNilList(*update.Domains)
+		columns = append(columns, "domains")
+	}
+	if update.Model != nil {
+		permission.Model = *update.Model
+		columns = append(columns, "model")
+	}
+	if update.Adapter != nil {
+		permission.Adapter = *update.Adapter
+		columns = append(columns, "adapter")
+	}
+	if update.ResourceType != nil {
+		permission.ResourceType = *update.ResourceType
+		columns = append(columns, "resource_type")
+	}
+	if update.Resources != nil {
+		permission.Resources = nonNilList(*update.Resources)
+		columns = append(columns, "resources")
+	}
+	if update.Actions != nil {
+		permission.Actions = nonNilList(*update.Actions)
+		columns = append(columns, "actions")
+	}
+	if update.Effect != nil {
+		permission.Effect = *update.Effect
+		columns = append(columns, "effect")
+	}
+	if update.Enabled != nil {
+		permission.IsEnabled = *update.Enabled
+		columns = append(columns, "is_enabled")
+	}
+	if update.State != nil {
+		permission.State = *update.State
+		columns = append(columns, "state")
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("nothing to update on permission %v", name)
+	}
+	if err = checkPermissionFields(permission.Effect, permission.State); err != nil {
+		return err
+	}
+
+	_, err = um.client.UpdatePermissionForColumns(permission, columns)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v permission has been updated successfully", name)
+	return nil
+}
+
+// DeletePermission deletes a Casdoor permission.
+func (um *UserManager) DeletePermission(name string) error {
+	permission, err := um.findPermission(name)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DeletePermission(permission)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v permission has been deleted successfully", name)
+	return nil
+}
+
+// ReviewPermission approves or rejects a permission pending approval on behalf of the approver.
+func (um *UserManager) ReviewPermission(name string, approve bool, approver string) error {
+	permission, err := um.findPermission(name)
+	if err != nil {
+		return err
+	}
+	if permission.State != PermissionPending {
+		return fmt.Errorf("permission %v is not pending approval (state: %v)", name, permission.State)
+	}
+
+	permission.State = PermissionRejected
+	if approve {
+		permission.State = PermissionApproved
+	}
+	permission.Approver = approver
+	permission.ApproveTime = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+	_, err = um.client.UpdatePermissionForColumns(permission, []string{"state", "approver", "approve_time"})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v permission is now %v", name, strings.ToLower(permission.State))
+	return nil
+}
+
+func (um *UserManager) findPermission(name string) (*casdoorsdk.Permission, error) {
+	permission, err := um.client.GetPermission(name)
+	if err != nil {
+		return nil, err
+	}
+	if permission == nil {
+		return nil, fmt.Errorf("permission %v doesn't exist", name)
+	}
+	return permission, nil
+}
+
+func checkPermissionFields(effect string, state string) error {
+	if effect != "Allow" && effect != "Deny" {
+		return fmt.Errorf("invalid effect %v (expected Allow or Deny)", effect)
+	}
+	if state != PermissionPending && state != PermissionApproved && state != PermissionRejected {
+		return fmt.Errorf("invalid state %v (expected %v, %v or %v)", state, PermissionPending, PermissionApproved, PermissionRejected)
+	}
+	return nil
+}
+
+func permissionInfo(permission *casdoorsdk.Permission) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":         permission.Name,
+		"Users":        strings.Join(groupNames(permission.Users), ", "),
+		"Roles":        strings.Join(groupNames(permission.Roles), ", "),
+		"ResourceType": permission.ResourceType,
+		"Resources":    strings.Join(permission.Resources, ", "),
+		"Actions":      strings.Join(permission.Actions, ", "),
+		"Effect":       permission.Effect,
+		"Enabled":      permission.IsEnabled,
+		"State":        permission.State,
+	}
+}
@@ -0,0 +1,133 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"strings"
+)
+
+// PolicyRule allows the groups or roles of Allow to run the commands matching Command.
+// Command is a dot separated command path such as "users.delete", where "*" matches a
+// single segment and "**" matches any number of segments. An Allow entry of "*" allows
+// every logged-in user.
+type PolicyRule struct {
+	Command string   `mapstructure:"command"`
+	Allow   []string `mapstructure:"allow"`
+}
+
+// Policy is an ordered list of rules. The first rule matching a command applies.
+type Policy struct {
+	Rules  []PolicyRule
+	Source string
+}
+
+// DefaultPolicyRules is the policy used when no policy file exists. Read-only commands are
+// open to lectors, creating users and groups to editors, and everything else to administrators.
+var DefaultPolicyRules = []PolicyRule{
+	{Command: "users.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "users.export", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "users.props.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "groups.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "groups.tree", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "groups.members.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "roles.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "roles.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "roles.tree", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "permissions.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "permissions.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "models.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "models.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "models.diff", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "enforcers.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "enforcers.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "adapters.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "adapters.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "policies.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "policies.export", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "policy.test", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "orgs.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "orgs.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "apps.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "apps.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "apps.providers.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "certs.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "certs.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "certs.export", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "certs.check", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "providers.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "providers.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "webhooks.list", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "webhooks.get", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "records.search", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "records.tail", Allow: []string{"administrator", "editor", "lector"}},
+	{Command: "users.add", Allow: []string{"administrator", "editor"}},
+	{Command: "users.check-password", Allow: []string{"administrator", "editor"}},
+	{Command: "groups.add", Allow: []string{"administrator", "editor"}},
+	{Command: "**", Allow: []string{"administrator"}},
+}
+
+// LoadPolicy reads the policy file at path. The default policy is returned when the file doesn't exist.
+func LoadPolicy(path string) (*Policy, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return &Policy{Rules: DefaultPolicyRules, Source: "default"}, nil
+	}
+
+	policyViper := viper.New()
+	policyViper.SetConfigFile(path)
+	err := policyViper.ReadInConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy file %v: %v", path, err)
+	}
+
+	var rules []PolicyRule
+	err = policyViper.UnmarshalKey("rules", &rules)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing policy file %v: %v", path, err)
+	}
+	for _, rule := range rules {
+		if rule.Command == "" {
+			return nil, fmt.Errorf("error parsing policy file %v: a rule has no command", path)
+		}
+	}
+	return &Policy{Rules: rules, Source: path}, nil
+}
+
+// Match returns the first rule matching the command path.
+func (p *Policy) Match(commandPath string) (PolicyRule, bool) {
+	for _, rule := range p.Rules {
+		if MatchCommand(rule.Command, commandPath) {
+			return rule, true
+		}
+	}
+	return PolicyRule{}, false
+}
+
+// Allows returns true if one of the groups is allowed by the rule.
+func (r PolicyRule) Allows(groups []string) bool {
+	return containsString(r.Allow, "*") || HasRequiredGroup(groups, r.Allow)
+}
+
+// MatchCommand returns true if the dot separated command path matches the pattern.
+func MatchCommand(pattern string, commandPath string) bool {
+	return matchSegments(strings.Split(pattern, "."), strings.Split(commandPath, "."))
+}
+
+func matchSegments(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 || (pattern[0] != "*" && pattern[0] != path[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], path[1:])
+}
@@ -0,0 +1,166 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// PolicyEvaluation is the outcome of an offline authorization request. Matched is the policy
+// line which decided the outcome, if any, and RoleChain the roles leading from the subject of the
+// request to the subject of that policy.
+type PolicyEvaluation struct {
+	Allowed   bool
+	Matched   []string
+	RoleChain []string
+	Roles     []string
+}
+
+// EvaluatePolicy enforces a request locally with a Casbin model file and a Casbin policy CSV file,
+// such as the ones written by models get and policies export. Nothing is sent to Casdoor. The model
+// is loaded by Casbin itself, so that any model Casdoor enforces can be simulated.
+func EvaluatePolicy(modelPath string, policyPath string, request []string) (*PolicyEvaluation, error) {
+	text, err := readModelFile(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	casbinModel, err := model.NewModelFromString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model %v: %v", modelPath, err)
+	}
+	if _, err = os.Stat(policyPath); err != nil {
+		return nil, err
+	}
+
+	enforcer, err := casbin.NewEnforcer(casbinModel, fileadapter.NewAdapter(policyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var rvals []interface{}
+	for _, value := range request {
+		rvals = append(rvals, value)
+	}
+	allowed, explain, err := enforcer.EnforceEx(rvals...)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := &PolicyEvaluation{Allowed: allowed}
+	if len(explain) > 0 {
+		evaluation.Matched = append([]string{matchedPolicyType(enforcer, explain)}, explain...)
+	}
+	if _, ok := enforcer.GetModel()["g"]["g"]; !ok {
+		return evaluation, nil
+	}
+
+	// roles with domains are only looked up within the domain of the request
+	var domain []string
+	if index := roleDomainIndex(enforcer); index >= 0 && index < len(request) {
+		domain = []string{request[index]}
+	}
+	evaluation.Roles, err = enforcer.GetImplicitRolesForUser(request[0], domain...)
+	if err != nil {
+		return nil, err
+	}
+	if len(explain) > 0 && explain[0] != request[0] {
+		evaluation.RoleChain, err = roleChain(enforcer, request[0], explain[0], domain...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return evaluation, nil
+}
+
+// ModelRequestTokens returns the names of the values of the request definition of a model file,
+// such as sub, obj and act.
+func ModelRequestTokens(modelPath string) ([]string, error) {
+	text, err := readModelFile(modelPath)
+	if err != nil {
+		return nil, err
+	}
+	casbinModel, err := model.NewModelFromString(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid model %v: %v", modelPath, err)
+	}
+	assertion, ok := casbinModel["r"]["r"]
+	if !ok {
+		return nil, fmt.Errorf("invalid model %v: missing request definition", modelPath)
+	}
+	var tokens []string
+	for _, token := range assertion.Tokens {
+		tokens = append(tokens, strings.TrimPrefix(token, "r_"))
+	}
+	return tokens, nil
+}
+
+// matchedPolicyType returns the policy type, such as p or p2, of the policy line which decided a
+// request.
+func matchedPolicyType(enforcer *casbin.Enforcer, rule []string) string {
+	policies := enforcer.GetModel()["p"]
+	var ptypes []string
+	for ptype := range policies {
+		ptypes = append(ptypes, ptype)
+	}
+	sort.Strings(ptypes)
+	for _, ptype := range ptypes {
+		for _, policy := range policies[ptype].Policy {
+			if slices.Equal(policy, rule) {
+				return ptype
+			}
+		}
+	}
+	return "p"
+}
+
+// roleDomainIndex returns the index of the request value the g role definition is matched with as
+// its domain, such as dom in g(r.sub, p.sub, r.dom), or -1 when roles have no domain.
+func roleDomainIndex(enforcer *casbin.Enforcer) int {
+	casbinModel := enforcer.GetModel()
+	if strings.Count(casbinModel["g"]["g"].Value, "_") < 3 {
+		return -1
+	}
+	matcher := roleDomainPattern.FindStringSubmatch(casbinModel["m"]["m"].Value)
+	if matcher == nil {
+		return -1
+	}
+	return slices.Index(casbinModel["r"]["r"].Tokens, "r_"+matcher[1])
+}
+
+var roleDomainPattern = regexp.MustCompile(`\bg\(\s*r_\w+\s*,\s*p_\w+\s*,\s*r_(\w+)\s*\)`)
+
+// roleChain returns the shortest chain of role assignments leading from name to role, both included,
+// within the given domain if any.
+func roleChain(enforcer *casbin.Enforcer, name string, role string, domain ...string) ([]string, error) {
+	previous := map[string]string{name: ""}
+	queue := []string{name}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == role {
+			chain := []string{current}
+			for previous[current] != "" {
+				current = previous[current]
+				chain = append([]string{current}, chain...)
+			}
+			return chain, nil
+		}
+		roles, err := enforcer.GetRolesForUser(current, domain...)
+		if err != nil {
+			return nil, err
+		}
+		for _, next := range roles {
+			if _, seen := previous[next]; !seen {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil, nil
+}
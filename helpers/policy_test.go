@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchCommand(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"users.delete", "users.delete", true},
+		{"users.delete", "users.add", false},
+		{"users.*", "users.delete", true},
+		{"users.*", "users", false},
+		{"users.*", "users.groups.add", false},
+		{"*.delete", "groups.delete", true},
+		{"users.**", "users", true},
+		{"users.**", "users.delete", true},
+		{"users.**", "users.groups.add", true},
+		{"users.**", "groups.delete", false},
+		{"**.delete", "delete", true},
+		{"**.delete", "users.groups.delete", true},
+		{"**.delete", "users.groups.add", false},
+		{"**", "records.export", true},
+		{"users.**.add", "users.add", true},
+		{"users.**.add", "users.groups.add", true},
+		{"users.**.add", "users.groups.remove", false},
+	}
+	for _, test := range tests {
+		if got := MatchCommand(test.pattern, test.path); got != test.want {
+			t.Errorf("MatchCommand(%q, %q) = %v, want %v", test.pattern, test.path, got, test.want)
+		}
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	policy, err := LoadPolicy(filepath.Join(dir, "missing.yaml"))
+	if err != nil || policy.Source != "default" {
+		t.Fatalf("LoadPolicy(missing) = %+v, %v, want the default policy", policy, err)
+	}
+
+	path := filepath.Join(dir, "policy.yaml")
+	content := `rules:
+  - command: users.set-password
+    allow: [helpdesk]
+  - command: users.*
+    allow: ["*"]
+  - command: "**"
+    allow: [administrator]
+`
+	if err = os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy(%v) failed: %v", path, err)
+	}
+	tests := []struct {
+		path    string
+		groups  []string
+		command string
+		allowed bool
+	}{
+		{"users.set-password", []string{"helpdesk"}, "users.set-password", true},
+		{"users.set-password", []string{"lector"}, "users.set-password", false},
+		{"users.list", []string{"lector"}, "users.*", true},
+		{"groups.delete", []string{"helpdesk"}, "**", false},
+		{"groups.delete", []string{"administrator"}, "**", true},
+	}
+	for _, test := range tests {
+		rule, ok := policy.Match(test.path)
+		if !ok || rule.Command != test.command {
+			t.Errorf("Match(%q) = %v, %v, want the rule %v", test.path, rule.Command, ok, test.command)
+			continue
+		}
+		if got := rule.Allows(test.groups); got != test.allowed {
+			t.Errorf("rule %v allows %v = %v, want %v", rule.Command, test.groups, got, test.allowed)
+		}
+	}
+
+	if err = os.WriteFile(path, []byte("rules:\n  - allow: [editor]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadPolicy(path); err == nil {
+		t.Errorf("LoadPolicy accepted a rule without command")
+	}
+}
@@ -0,0 +1,98 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// PolicyTestCase is an authorization request along with its expected outcome, "allow" or "deny".
+type PolicyTestCase struct {
+	Name     string `mapstructure:"name"`
+	Subject  string `mapstructure:"subject"`
+	Object   string `mapstructure:"object"`
+	Action   string `mapstructure:"action"`
+	Expected string `mapstructure:"expected"`
+}
+
+// PolicyTestSuite is a set of test cases run against a Casdoor permission or model.
+type PolicyTestSuite struct {
+	Permission string           `mapstructure:"permission"`
+	Model      string           `mapstructure:"model"`
+	Cases      []PolicyTestCase `mapstructure:"cases"`
+}
+
+// PolicyTestResult is the outcome of a test case.
+type PolicyTestResult struct {
+	Case    PolicyTestCase
+	Allowed bool
+	Passed  bool
+}
+
+// LoadPolicyTestSuite reads a YAML file of test cases, such as:
+//
+//	permission: my-org/my-permission
+//	cases:
+//	  - name: editors cannot delete users
+//	    subject: my-org/bob
+//	    object: users
+//	    action: delete
+//	    expected: deny
+func LoadPolicyTestSuite(path string) (*PolicyTestSuite, error) {
+	suiteViper := viper.New()
+	suiteViper.SetConfigFile(path)
+	suiteViper.SetConfigType("yaml")
+	err := suiteViper.ReadInConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error reading test cases %v: %v", path, err)
+	}
+
+	var suite PolicyTestSuite
+	err = suiteViper.Unmarshal(&suite)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing test cases %v: %v", path, err)
+	}
+	if len(suite.Cases) == 0 {
+		return nil, fmt.Errorf("no test case found in %v", path)
+	}
+	for i, testCase := range suite.Cases {
+		if testCase.Subject == "" || testCase.Object == "" || testCase.Action == "" {
+			return nil, fmt.Errorf("test case %d of %v needs a subject, an object and an action", i+1, path)
+		}
+		expected := strings.ToLower(testCase.Expected)
+		if expected != "allow" && expected != "deny" {
+			return nil, fmt.Errorf("test case %d of %v expects %q (expected allow or deny)", i+1, path, testCase.Expected)
+		}
+		suite.Cases[i].Expected = expected
+		if testCase.Name == "" {
+			suite.Cases[i].Name = fmt.Sprintf("%s %s %s", testCase.Subject, testCase.Action, testCase.Object)
+		}
+	}
+	return &suite, nil
+}
+
+// RunPolicyTests enforces every test case of the suite in a single BatchEnforce call.
+func (um *UserManager) RunPolicyTests(suite *PolicyTestSuite) ([]PolicyTestResult, error) {
+	if suite.Permission == "" && suite.Model == "" {
+		return nil, fmt.Errorf("a permission or a model to test against is required")
+	}
+
+	var requests [][]string
+	for _, testCase := range suite.Cases {
+		requests = append(requests, []string{testCase.Subject, testCase.Object, testCase.Action})
+	}
+	allowed, err := um.BatchEnforce(suite.Permission, suite.Model, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []PolicyTestResult
+	for i, testCase := range suite.Cases {
+		results = append(results, PolicyTestResult{
+			Case:    testCase,
+			Allowed: allowed[i],
+			Passed:  allowed[i] == (testCase.Expected == "allow"),
+		})
+	}
+	return results, nil
+}
@@ -0,0 +1,105 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"sort"
+	"strings"
+)
+
+// GetUserProperties returns the custom properties of the user. When keys are given,
+// only those properties are returned.
+func (um *UserManager) GetUserProperties(userName string, keys []string) ([]map[string]interface{}, error) {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		for key := range user.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	var propertyList []map[string]interface{}
+	for _, key := range keys {
+		value, ok := user.Properties[key]
+		if !ok {
+			continue
+		}
+		propertyInfo := map[string]interface{}{
+			"Key":   key,
+			"Value": value,
+		}
+
+		propertyList = append(propertyList, propertyInfo)
+	}
+	return propertyList, nil
+}
+
+// SetUserProperties sets the given properties on the user, keeping the other ones.
+// Only the properties column is written so that every other field stays untouched.
+func (um *UserManager) SetUserProperties(userName string, properties map[string]string) error {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return err
+	}
+
+	if user.Properties == nil {
+		user.Properties = map[string]string{}
+	}
+	for key, value := range properties {
+		user.Properties[key] = value
+	}
+
+	_, err = um.client.UpdateUserForColumns(user, []string{"properties"})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] properties of %v have been updated successfully", userName)
+	return nil
+}
+
+// UnsetUserProperties removes the given properties from the user.
+func (um *UserManager) UnsetUserProperties(userName string, keys []string) error {
+	user, err := um.findUser(userName)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		delete(user.Properties, key)
+	}
+
+	_, err = um.client.UpdateUserForColumns(user, []string{"properties"})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] properties of %v have been updated successfully", userName)
+	return nil
+}
+
+// ParseProperties parses "key=value" arguments into a map.
+func ParseProperties(args []string) (map[string]string, error) {
+	properties := map[string]string{}
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid property %v, expected key=value", arg)
+		}
+		properties[key] = value
+	}
+	return properties, nil
+}
+
+// matchProperties returns true if the user properties hold every one of the given properties.
+func matchProperties(userProperties map[string]string, filters map[string]string) bool {
+	for key, value := range filters {
+		if userProperties[key] != value {
+			return false
+		}
+	}
+	return true
+}
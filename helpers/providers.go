@@ -0,0 +1,382 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// smtpTimeout bounds the whole SMTP conversation of a provider test.
+const smtpTimeout = 30 * time.Second
+
+// ProviderCategories are the categories of Casdoor providers.
+var ProviderCategories = []string{"OAuth", "Email", "SMS", "Storage", "SAML", "Payment", "Captcha", "Web3", "Notification"}
+
+// ProviderFields holds the fields of a new provider besides its name. For Email providers, ClientId
+// and ClientSecret are the SMTP username and password.
+type ProviderFields struct {
+	DisplayName  string
+	Category     string
+	Type         string
+	SubType      string
+	ClientId     string
+	ClientSecret string
+	Host         string
+	Port         int
+	Endpoint     string
+	RegionId     string
+	SignName     string
+	TemplateCode string
+	Bucket       string
+	Domain       string
+}
+
+// ProviderUpdate holds the fields to change on a provider. Nil fields are preserved.
+type ProviderUpdate struct {
+	DisplayName  *string
+	SubType      *string
+	ClientId     *string
+	ClientSecret *string
+	Host         *string
+	Port         *int
+	Endpoint     *string
+	RegionId     *string
+	SignName     *string
+	TemplateCode *string
+	Bucket       *string
+	Domain       *string
+}
+
+// GetProviders returns the providers of the organization, optionally of a category and a type.
+// Secrets are masked unless showSecrets is set.
+func (um *UserManager) GetProviders(category string, providerType string, showSecrets bool) ([]map[string]interface{}, error) {
+	providers, err := um.client.GetProviders()
+	if err != nil {
+		return nil, err
+	}
+	var providerList []map[string]interface{}
+
+	for _, provider := range providers {
+		if category != "" && !strings.EqualFold(provider.Category, category) {
+			continue
+		}
+		if providerType != "" && !strings.EqualFold(provider.Type, providerType) {
+			continue
+		}
+		providerList = append(providerList, map[string]interface{}{
+			"Name":         provider.Name,
+			"Category":     provider.Category,
+			"Type":         provider.Type,
+			"ClientId":     provider.ClientId,
+			"ClientSecret": shownSecret(provider.ClientSecret, showSecrets),
+			"Host":         provider.Host,
+		})
+	}
+	return providerList, nil
+}
+
+// GetProvider returns the settings of a provider. Its secrets are masked unless showSecrets is set.
+func (um *UserManager) GetProvider(name string, showSecrets bool) (map[string]interface{}, error) {
+	provider, _, err := um.findProvider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := map[string]interface{}{
+		"Name":          provider.Name,
+		"Owner":         provider.Owner,
+		"DisplayName":   provider.DisplayName,
+		"Category":      provider.Category,
+		"Type":          provider.Type,
+		"SubType":       provider.SubType,
+		"ClientId":      provider.ClientId,
+		"ClientSecret":  shownSecret(provider.ClientSecret, showSecrets),
+		"ClientSecret2": shownSecret(provider.ClientSecret2, showSecrets),
+	}
+	// only the settings relevant to the category are shown
+	switch provider.Category {
+	case "Email":
+		info["Host"] = provider.Host
+		info["Port"] = provider.Port
+		info["DisableSsl"] = provider.DisableSsl
+	case "SMS":
+		info["RegionId"] = provider.RegionId
+		info["SignName"] = provider.SignName
+		info["TemplateCode"] = provider.TemplateCode
+	case "Storage":
+		info["Endpoint"] = provider.Endpoint
+		info["RegionId"] = provider.RegionId
+		info["Bucket"] = provider.Bucket
+		info["Domain"] = provider.Domain
+	}
+	return info, nil
+}
+
+// AddProvider creates a provider in the organization.
+func (um *UserManager) AddProvider(name string, fields ProviderFields) error {
+	existing, err := um.client.GetProvider(name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("provider %v already exists", name)
+	}
+	if !containsString(ProviderCategories, fields.Category) {
+		return fmt.Errorf("invalid category %v (expected one of %v)", fields.Category, strings.Join(ProviderCategories, ", "))
+	}
+	if fields.Type == "" {
+		return fmt.Errorf("the type of the provider is required, such as Default for SMTP or Aliyun SMS")
+	}
+
+	displayName := fields.DisplayName
+	if displayName == "" {
+		displayName = name
+	}
+	_, err = um.client.AddProvider(&casdoorsdk.Provider{
+		Owner:        um.client.OrganizationName,
+		Name:         name,
+		CreatedTime:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		DisplayName:  displayName,
+		Category:     fields.Category,
+		Type:         fields.Type,
+		SubType:      fields.SubType,
+		ClientId:     fields.ClientId,
+		ClientSecret: fields.ClientSecret,
+		Host:         fields.Host,
+		Port:         fields.Port,
+		Endpoint:     fields.Endpoint,
+		RegionId:     fields.RegionId,
+		SignName:     fields.SignName,
+		TemplateCode: fields.TemplateCode,
+		Bucket:       fields.Bucket,
+		Domain:       fields.Domain,
+	})
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v provider has been created successfully", name)
+	return nil
+}
+
+// UpdateProvider changes the given settings of a provider and preserves the other ones.
+func (um *UserManager) UpdateProvider(name string, update ProviderUpdate) error {
+	provider, raw, err := um.findProvider(name)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for key, value := range map[string]*string{
+		"displayName":  update.DisplayName,
+		"subType":      update.SubType,
+		"clientId":     update.ClientId,
+		"clientSecret": update.ClientSecret,
+		"host":         update.Host,
+		"endpoint":     update.Endpoint,
+		"regionId":     update.RegionId,
+		"signName":     update.SignName,
+		"templateCode": update.TemplateCode,
+		"bucket":       update.Bucket,
+		"domain":       update.Domain,
+	} {
+		if value != nil {
+			raw[key] = *value
+			changed = true
+		}
+	}
+	if update.Port != nil {
+		raw["port"] = *update.Port
+		changed = true
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update on provider %v", name)
+	}
+
+	err = um.updateRawObject("update-provider", fmt.Sprintf("%s/%s", provider.Owner, provider.Name), raw)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v provider has been updated successfully", name)
+	return nil
+}
+
+// DeleteProvider deletes a provider. A provider still used by applications is only deleted with force.
+func (um *UserManager) DeleteProvider(name string, force bool) error {
+	provider, _, err := um.findProvider(name)
+	if err != nil {
+		return err
+	}
+
+	applications, err := um.client.GetOrganizationApplications()
+	if err != nil {
+		return err
+	}
+	var usedBy []string
+	for _, application := range applications {
+		for _, item := range application.Providers {
+			if item.Name == name {
+				usedBy = append(usedBy, application.Name)
+			}
+		}
+	}
+	if len(usedBy) > 0 {
+		if !force {
+			return fmt.Errorf("provider %v is used by the applications %v, use --force to delete it anyway", name, strings.Join(usedBy, ", "))
+		}
+		utils.Colorize(color.YellowString, "[⚠] provider %v is used by the applications %v", name, strings.Join(usedBy, ", "))
+	}
+
+	postBytes, err := json.Marshal(provider)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DoPost("delete-provider", map[string]string{"id": fmt.Sprintf("%s/%s", provider.Owner, provider.Name)}, postBytes, false, false)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v provider has been deleted successfully", name)
+	return nil
+}
+
+// TestProvider sends a test message to the receiver through an Email or an SMS provider. Email
+// providers are tested by connecting to their SMTP server directly, with the same settings Casdoor
+// uses, so the providers of any organization can be tested. SMS providers are tested through the
+// send-sms request along with the provider parameter, which Casdoor looks up among the providers of
+// admin only.
+func (um *UserManager) TestProvider(name string, receiver string) error {
+	provider, _, err := um.findProvider(name)
+	if err != nil {
+		return err
+	}
+
+	content := fmt.Sprintf("This is a test message sent by casdoor-cli through the %v provider.", name)
+	switch provider.Category {
+	case "Email":
+		err = sendTestEmail(provider, receiver, "Casdoor provider test", content)
+	case "SMS":
+		if provider.Owner != "admin" {
+			return fmt.Errorf("provider %v belongs to %v, and Casdoor only sends SMS test messages through the providers of admin", name, provider.Owner)
+		}
+		var postBytes []byte
+		postBytes, err = json.Marshal(map[string]interface{}{
+			"content":   content,
+			"receivers": []string{receiver},
+		})
+		if err != nil {
+			return err
+		}
+		_, err = um.client.DoPost("send-sms", map[string]string{"provider": provider.Name}, postBytes, false, false)
+	default:
+		return fmt.Errorf("only Email and SMS providers can be tested, %v is a %v provider", name, provider.Category)
+	}
+	if err != nil {
+		return fmt.Errorf("test of provider %v failed: %v", name, err)
+	}
+	utils.Colorize(color.GreenString, "[✔] test message sent to %v through %v", receiver, name)
+	return nil
+}
+
+// sendTestEmail sends a message through the SMTP server of an Email provider. Like Casdoor, the
+// connection uses TLS unless DisableSsl is set, in which case STARTTLS is used when the server
+// offers it. ClientId and ClientSecret are the SMTP username and password, and the message is sent
+// from ClientId2, or from ClientId when it is empty.
+func sendTestEmail(provider *casdoorsdk.Provider, receiver string, subject string, content string) error {
+	address := net.JoinHostPort(provider.Host, strconv.Itoa(provider.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	var err error
+	if provider.DisableSsl {
+		conn, err = dialer.Dial("tcp", address)
+	} else {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: provider.Host})
+	}
+	if err != nil {
+		return err
+	}
+	err = conn.SetDeadline(time.Now().Add(smtpTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, provider.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && provider.DisableSsl {
+		if err = client.StartTLS(&tls.Config{ServerName: provider.Host}); err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok && provider.ClientId != "" {
+		if err = client.Auth(smtp.PlainAuth("", provider.ClientId, provider.ClientSecret, provider.Host)); err != nil {
+			return err
+		}
+	}
+
+	from := provider.ClientId2
+	if from == "" {
+		from = provider.ClientId
+	}
+	if err = client.Mail(from); err != nil {
+		return err
+	}
+	if err = client.Rcpt(receiver); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("From: casdoor-cli <%s>\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\n\r\n%s\r\n",
+		from, receiver, subject, time.Now().Format(time.RFC1123Z), content)
+	if _, err = writer.Write([]byte(message)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// findProvider looks for a provider in the organization first, and then among the ones of admin,
+// such as provider_captcha_default. The provider is returned both parsed and as raw JSON.
+func (um *UserManager) findProvider(name string) (*casdoorsdk.Provider, map[string]interface{}, error) {
+	provider, err := um.client.GetProvider(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	owner := um.client.OrganizationName
+	if provider == nil {
+		owner = "admin"
+	}
+	raw, err := um.getRawObject("get-provider", fmt.Sprintf("%s/%s", owner, name))
+	if errors.As(err, &notFoundError{}) {
+		return nil, nil, fmt.Errorf("provider %v doesn't exist", name)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = json.Unmarshal(data, &provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	return provider, raw, nil
+}
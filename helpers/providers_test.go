@@ -0,0 +1,147 @@
+package helpers
+
+import (
+	"bufio"
+	"encoding/base64"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+)
+
+// smtpSession is what the test SMTP server received during one conversation.
+type smtpSession struct {
+	auth string
+	from string
+	rcpt string
+	data string
+}
+
+// serveSMTP answers a single SMTP conversation on the listener. The AUTH extension is offered when
+// offerAuth is set, and recipients other than accept are refused.
+func serveSMTP(listener net.Listener, offerAuth bool, accept string, done chan<- smtpSession) {
+	var session smtpSession
+	defer func() { done <- session }()
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 localhost ESMTP test")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO":
+			if offerAuth {
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			} else {
+				reply("250 localhost")
+			}
+		case "AUTH":
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			session.auth = string(decoded)
+			reply("235 authenticated")
+		case "MAIL":
+			session.from = strings.TrimSuffix(strings.TrimPrefix(line, "MAIL FROM:<"), ">")
+			reply("250 ok")
+		case "RCPT":
+			rcpt := strings.TrimSuffix(strings.TrimPrefix(line, "RCPT TO:<"), ">")
+			if rcpt != accept {
+				reply("550 no such user")
+				continue
+			}
+			session.rcpt = rcpt
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			session.data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSendTestEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		offerAuth bool
+		clientId2 string
+		receiver  string
+		wantErr   bool
+		wantAuth  string
+		wantFrom  string
+	}{
+		{"auth", true, "", "bob@example.com", false, "\x00alice@example.com\x00secret", "alice@example.com"},
+		{"no auth offered", false, "", "bob@example.com", false, "", "alice@example.com"},
+		{"from address", true, "noreply@example.com", "bob@example.com", false, "\x00alice@example.com\x00secret", "noreply@example.com"},
+		{"refused receiver", true, "", "eve@example.com", true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer listener.Close()
+			done := make(chan smtpSession, 1)
+			go serveSMTP(listener, tt.offerAuth, "bob@example.com", done)
+
+			provider := &casdoorsdk.Provider{
+				Category:     "Email",
+				Host:         "127.0.0.1",
+				Port:         listener.Addr().(*net.TCPAddr).Port,
+				DisableSsl:   true,
+				ClientId:     "alice@example.com",
+				ClientSecret: "secret",
+				ClientId2:    tt.clientId2,
+			}
+			err = sendTestEmail(provider, tt.receiver, "Casdoor provider test", "hello")
+			session := <-done
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sendTestEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if session.auth != tt.wantAuth {
+				t.Errorf("auth = %q, want %q", session.auth, tt.wantAuth)
+			}
+			if session.from != tt.wantFrom {
+				t.Errorf("from = %q, want %q", session.from, tt.wantFrom)
+			}
+			if session.rcpt != tt.receiver {
+				t.Errorf("rcpt = %q, want %q", session.rcpt, tt.receiver)
+			}
+			if !strings.Contains(session.data, "Subject: Casdoor provider test\r\n") || !strings.Contains(session.data, "\r\n\r\nhello\r\n") {
+				t.Errorf("unexpected message %q", session.data)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RecordCursors records the id of the last audit record each export checkpoint or forwarder has
+// handled, so that the next run only handles the newer records. The cursors are stored locally next
+// to the config.
+type RecordCursors struct {
+	path    string
+	Entries map[string]RecordCursor `json:"entries"`
+}
+
+// RecordCursor is the last record handled under a cursor name.
+type RecordCursor struct {
+	LastId    int       `json:"lastId"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// LoadRecordCursors reads the cursors stored at path. A missing file is treated as empty.
+func LoadRecordCursors(path string) (*RecordCursors, error) {
+	cursors := &RecordCursors{path: path, Entries: map[string]RecordCursor{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, cursors)
+	if err != nil {
+		return nil, fmt.Errorf("error reading record cursors %v: %v", path, err)
+	}
+	return cursors, nil
+}
+
+// LastId returns the id of the last record handled under a cursor name, and false if there is none.
+func (c *RecordCursors) LastId(name string) (int, bool) {
+	cursor, ok := c.Entries[name]
+	return cursor.LastId, ok
+}
+
+// Save moves a cursor to the given record id and writes the cursors back to disk.
+func (c *RecordCursors) Save(name string, lastId int) error {
+	c.Entries[name] = RecordCursor{LastId: lastId, UpdatedAt: time.Now().UTC()}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0600)
+}
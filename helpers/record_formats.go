@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"strings"
+)
+
+// RecordFormats are the formats records are exported and forwarded in: one JSON object per line,
+// ArcSight Common Event Format, or IBM QRadar Log Event Extended Format.
+var RecordFormats = []string{"ndjson", "cef", "leef"}
+
+// CheckRecordFormat returns an error when format isn't one of RecordFormats.
+func CheckRecordFormat(format string) error {
+	if !containsString(RecordFormats, format) {
+		return fmt.Errorf("invalid format %v (expected one of %v)", format, strings.Join(RecordFormats, ", "))
+	}
+	return nil
+}
+
+// FormatRecord returns a record as a single line of the given format, without a trailing newline.
+// The actions deleting an object get a higher severity in CEF than the other ones.
+func FormatRecord(record *casdoorsdk.Record, format string) (string, error) {
+	switch format {
+	case "ndjson":
+		data, err := json.Marshal(record)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case "cef":
+		severity := 3
+		if strings.HasPrefix(record.Action, "delete-") {
+			severity = 7
+		}
+		extension := []string{
+			"rt=" + cefValue(fmt.Sprintf("%d", recordTime(record).UnixMilli())),
+			"externalId=" + cefValue(fmt.Sprintf("%d", record.Id)),
+			"suser=" + cefValue(record.User),
+			"src=" + cefValue(record.ClientIp),
+			"requestMethod=" + cefValue(record.Method),
+			"request=" + cefValue(record.RequestUri),
+			"act=" + cefValue(record.Action),
+			"cs1Label=organization",
+			"cs1=" + cefValue(record.Organization),
+		}
+		return fmt.Sprintf("CEF:0|Casdoor|Casdoor|1.0|%s|%s|%d|%s", cefHeader(record.Action), cefHeader(record.Action), severity, strings.Join(extension, " ")), nil
+	case "leef":
+		attributes := []string{
+			"devTime=" + leefValue(record.CreatedTime),
+			"devTimeFormat=yyyy-MM-dd'T'HH:mm:ssXXX",
+			"externalId=" + leefValue(fmt.Sprintf("%d", record.Id)),
+			"usrName=" + leefValue(record.User),
+			"src=" + leefValue(record.ClientIp),
+			"requestMethod=" + leefValue(record.Method),
+			"url=" + leefValue(record.RequestUri),
+			"organization=" + leefValue(record.Organization),
+		}
+		return fmt.Sprintf("LEEF:1.0|Casdoor|Casdoor|1.0|%s|%s", leefValue(record.Action), strings.Join(attributes, "\t")), nil
+	}
+	return "", CheckRecordFormat(format)
+}
+
+// cefHeader escapes a CEF header field, in which backslashes and pipes are escaped.
+func cefHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ", "\r", " ").Replace(value)
+}
+
+// cefValue escapes a CEF extension value, in which backslashes, equal signs and newlines are escaped.
+func cefValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "=", `\=`, "\n", `\n`, "\r", `\r`).Replace(value)
+}
+
+// leefValue removes the tabs and newlines which would split a LEEF event.
+func leefValue(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+}
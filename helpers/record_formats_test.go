@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+)
+
+func TestCefHeader(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"delete-user", "delete-user"},
+		{"a|b", `a\|b`},
+		{`a\b`, `a\\b`},
+		{"a=b", "a=b"},
+		{"a\nb\rc", "a b c"},
+	}
+	for _, test := range tests {
+		if got := cefHeader(test.value); got != test.want {
+			t.Errorf("cefHeader(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestCefValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice", "alice"},
+		{"a=b", `a\=b`},
+		{`a\b`, `a\\b`},
+		{"a|b", "a|b"},
+		{"a\nb\rc", `a\nb\rc`},
+	}
+	for _, test := range tests {
+		if got := cefValue(test.value); got != test.want {
+			t.Errorf("cefValue(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestLeefValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice", "alice"},
+		{"a\tb", "a b"},
+		{"a\nb\rc", "a b c"},
+		{"a|b=c", "a|b=c"},
+	}
+	for _, test := range tests {
+		if got := leefValue(test.value); got != test.want {
+			t.Errorf("leefValue(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestFormatRecord(t *testing.T) {
+	record := &casdoorsdk.Record{
+		Id:           42,
+		CreatedTime:  "2024-01-31T12:00:00Z",
+		Organization: "my-org",
+		User:         "alice\tsmith",
+		Action:       "delete-user|x=y\nz",
+		RequestUri:   "/api/delete-user?id=a=b",
+	}
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"cef", []string{`CEF:0|Casdoor|Casdoor|1.0|delete-user\|x=y z|delete-user\|x=y z|7|`, `request=/api/delete-user?id\=a\=b`, `act=delete-user|x\=y\nz`}},
+		{"leef", []string{"LEEF:1.0|Casdoor|Casdoor|1.0|delete-user|x=y z|", "usrName=alice smith\t", "url=/api/delete-user?id=a=b"}},
+		{"ndjson", []string{`"action":"delete-user|x=y\nz"`}},
+	}
+	for _, test := range tests {
+		line, err := FormatRecord(record, test.format)
+		if err != nil {
+			t.Fatalf("FormatRecord(%v) failed: %v", test.format, err)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("FormatRecord(%v) = %q, which spans several lines", test.format, line)
+		}
+		for _, want := range test.want {
+			if !strings.Contains(line, want) {
+				t.Errorf("FormatRecord(%v) = %q, want it to contain %q", test.format, line, want)
+			}
+		}
+	}
+	if _, err := FormatRecord(record, "xml"); err == nil {
+		t.Errorf("FormatRecord(xml) succeeded, want an error")
+	}
+}
@@ -0,0 +1,202 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// RecordSinkSchemes are the schemes of the destinations records are forwarded to.
+var RecordSinkSchemes = []string{"syslog", "tcp", "udp", "file"}
+
+// RecordSink is a destination of forwarded records: a syslog server over UDP (syslog://host:514),
+// a TCP or UDP listener receiving one record per line (tcp://host:port, udp://host:port), or a file
+// records are appended to (file:///var/log/casdoor.log).
+type RecordSink struct {
+	scheme   string
+	address  string
+	hostname string
+	writer   io.WriteCloser
+}
+
+// NewRecordSink parses the destination of forwarded records. The connection is opened on the first write.
+func NewRecordSink(to string) (*RecordSink, error) {
+	parsed, err := url.Parse(to)
+	if err != nil || !containsString(RecordSinkSchemes, parsed.Scheme) {
+		return nil, fmt.Errorf("invalid destination %v (expected syslog://host:514, tcp://host:port, udp://host:port or file:///path)", to)
+	}
+	sink := &RecordSink{scheme: parsed.Scheme}
+	switch parsed.Scheme {
+	case "file":
+		sink.address = parsed.Host + parsed.Path
+	case "syslog":
+		sink.address = parsed.Host
+		if parsed.Port() == "" {
+			sink.address = net.JoinHostPort(parsed.Hostname(), "514")
+		}
+	default:
+		sink.address = parsed.Host
+		if parsed.Port() == "" {
+			return nil, fmt.Errorf("the destination %v has no port", to)
+		}
+	}
+	if sink.address == "" {
+		return nil, fmt.Errorf("the destination %v has no address", to)
+	}
+	sink.hostname, _ = os.Hostname()
+	return sink, nil
+}
+
+// Write sends a formatted record to the sink. Syslog messages are RFC 5424 messages of the authpriv
+// facility, and every other destination gets one record per line.
+func (s *RecordSink) Write(record *casdoorsdk.Record, line string) error {
+	if s.writer == nil {
+		var err error
+		if s.scheme == "file" {
+			s.writer, err = os.OpenFile(s.address, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		} else {
+			network := s.scheme
+			if network == "syslog" {
+				network = "udp"
+			}
+			s.writer, err = net.DialTimeout(network, s.address, 10*time.Second)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	message := line + "\n"
+	if s.scheme == "syslog" {
+		timestamp := record.CreatedTime
+		if created := recordTime(record); !created.IsZero() {
+			timestamp = created.Format(time.RFC3339)
+		}
+		// priority 86 is the authpriv facility (10) with the informational severity (6)
+		message = fmt.Sprintf("<86>1 %s %s casdoor - - - %s", timestamp, s.hostname, line)
+	}
+	_, err := io.WriteString(s.writer, message)
+	if err != nil {
+		// the connection is opened again on the next write
+		s.Close()
+	}
+	return err
+}
+
+// Close closes the connection or the file of the sink.
+func (s *RecordSink) Close() error {
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Close()
+	s.writer = nil
+	return err
+}
+
+// ExportRecords returns the records matching the filter whose id is greater than afterId, oldest
+// first, with one record per line in the given format. The id of the last exported record and the
+// number of exported records are returned along with them.
+func (um *UserManager) ExportRecords(format string, filter RecordFilter, afterId int) (string, int, int, error) {
+	if err := CheckRecordFormat(format); err != nil {
+		return "", 0, 0, err
+	}
+	records, err := um.recordsAfter(afterId, filter)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	var exported strings.Builder
+	lastId := afterId
+	count := 0
+	// records come newest first, so they are exported backwards
+	for i := len(records) - 1; i >= 0; i-- {
+		if !filter.matches(records[i]) {
+			continue
+		}
+		line, err := FormatRecord(records[i], format)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		exported.WriteString(line + "\n")
+		lastId = records[i].Id
+		count++
+	}
+	return exported.String(), lastId, count, nil
+}
+
+// ForwardRecords sends the new records of the organization to a sink in the given format, polling
+// Casdoor every interval until the process is interrupted. The cursor of the given name is moved
+// after each batch, so that a restarted forwarder resumes after the last forwarded record. Without
+// a cursor, forwarding starts at since, or with the records created from now on when since is zero.
+func (um *UserManager) ForwardRecords(sink *RecordSink, format string, cursors *RecordCursors, name string, since time.Time, interval time.Duration) error {
+	if err := CheckRecordFormat(format); err != nil {
+		return err
+	}
+	lastId, ok := cursors.LastId(name)
+	filter := RecordFilter{}
+	if ok {
+		utils.Colorize(color.CyanString, "[ℹ] resuming after record %d", lastId)
+	} else if !since.IsZero() {
+		filter.Since = since
+	} else {
+		records, _, err := um.getRecordsPage(1, filter)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			lastId = records[0].Id
+		}
+		// the starting point is stored right away, so that a forwarder restarted before the first
+		// batch doesn't skip the records created meanwhile
+		if err = cursors.Save(name, lastId); err != nil {
+			return err
+		}
+	}
+
+	for {
+		var err error
+		lastId, err = um.forwardNewRecords(sink, format, cursors, name, lastId, filter)
+		if err != nil {
+			return err
+		}
+		time.Sleep(interval)
+	}
+}
+
+// forwardNewRecords forwards the records created after lastId, oldest first, and moves the cursor
+// after the last forwarded one. Failing to poll Casdoor or to write to the sink is only reported,
+// the records being forwarded again on the next call. The id of the last forwarded record is returned.
+func (um *UserManager) forwardNewRecords(sink *RecordSink, format string, cursors *RecordCursors, name string, lastId int, filter RecordFilter) (int, error) {
+	records, err := um.recordsAfter(lastId, filter)
+	if err != nil {
+		utils.Colorize(color.YellowString, "[⚠] failed to poll records: %v", err)
+	}
+	forwarded := 0
+	// records come newest first, so they are forwarded backwards
+	for i := len(records) - 1; i >= 0; i-- {
+		line, err := FormatRecord(records[i], format)
+		if err != nil {
+			return lastId, err
+		}
+		if err = sink.Write(records[i], line); err != nil {
+			utils.Colorize(color.YellowString, "[⚠] failed to forward record %d: %v", records[i].Id, err)
+			break
+		}
+		lastId = records[i].Id
+		forwarded++
+	}
+	if forwarded > 0 {
+		if err = cursors.Save(name, lastId); err != nil {
+			return lastId, err
+		}
+		utils.Colorize(color.GreenString, "[✔] %d records forwarded, up to record %d", forwarded, lastId)
+	}
+	return lastId, nil
+}
@@ -0,0 +1,238 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"gitlab.com/sdv9972401/casdoor-cli/models"
+)
+
+// recordServer serves the get-records request of Casdoor from a list of record ids, newest first.
+// The ids can be changed between requests to simulate records created meanwhile.
+type recordServer struct {
+	mu  sync.Mutex
+	ids []int
+	// onPage is called before a page is served
+	onPage func(page int)
+}
+
+func (s *recordServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("p"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if s.onPage != nil {
+		s.onPage(page)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []*casdoorsdk.Record
+	for i := (page - 1) * pageSize; i < page*pageSize && i < len(s.ids); i++ {
+		records = append(records, &casdoorsdk.Record{
+			Id:           s.ids[i],
+			Owner:        "org",
+			Organization: "org",
+			User:         "alice",
+			Action:       "login",
+			CreatedTime:  "2024-05-01T10:00:00Z",
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": records, "data2": len(s.ids)})
+}
+
+func (s *recordServer) setIds(ids ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids = ids
+}
+
+func newRecordServer(t *testing.T, ids ...int) (*recordServer, *UserManager) {
+	server := &recordServer{ids: ids}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	um := NewUserManager(&models.CasdoorConfig{Endpoint: httpServer.URL, OrganizationName: "org"})
+	return server, um
+}
+
+func forwardedIds(t *testing.T, path string) []int {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record casdoorsdk.Record
+		if err = json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid forwarded line %q: %v", line, err)
+		}
+		ids = append(ids, record.Id)
+	}
+	return ids
+}
+
+func TestRecordCursors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cursors.json")
+	cursors, err := LoadRecordCursors(path)
+	if err != nil {
+		t.Fatalf("LoadRecordCursors(missing) failed: %v", err)
+	}
+	if _, ok := cursors.LastId("siem"); ok {
+		t.Errorf("a missing file has the cursor siem")
+	}
+
+	if err = cursors.Save("siem", 42); err != nil {
+		t.Fatal(err)
+	}
+	if err = cursors.Save("archive", 7); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("cursors written with mode %v, want 0600", info.Mode().Perm())
+	}
+
+	cursors, err = LoadRecordCursors(path)
+	if err != nil {
+		t.Fatalf("LoadRecordCursors failed: %v", err)
+	}
+	for name, want := range map[string]int{"siem": 42, "archive": 7} {
+		if lastId, ok := cursors.LastId(name); !ok || lastId != want {
+			t.Errorf("LastId(%v) = %d, %v, want %d", name, lastId, ok, want)
+		}
+	}
+
+	if err = os.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadRecordCursors(path); err == nil {
+		t.Errorf("LoadRecordCursors accepted an invalid file")
+	}
+}
+
+func TestForwardNewRecords(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "records.log")
+	server, um := newRecordServer(t, 5, 4, 3, 2, 1)
+	sink, err := NewRecordSink("file://" + output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	cursors, err := LoadRecordCursors(filepath.Join(dir, "cursors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		ids       []int
+		lastId    int
+		want      int
+		forwarded []int
+	}{
+		{"new records", []int{5, 4, 3, 2, 1}, 2, 5, []int{3, 4, 5}},
+		{"nothing new", []int{5, 4, 3, 2, 1}, 5, 5, []int{3, 4, 5}},
+		{"more records", []int{7, 6, 5, 4, 3, 2, 1}, 5, 7, []int{3, 4, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		server.setIds(tt.ids...)
+		lastId, err := um.forwardNewRecords(sink, "ndjson", cursors, "siem", tt.lastId, RecordFilter{})
+		if err != nil {
+			t.Fatalf("%v: forwardNewRecords failed: %v", tt.name, err)
+		}
+		if lastId != tt.want {
+			t.Errorf("%v: forwardNewRecords() = %d, want %d", tt.name, lastId, tt.want)
+		}
+		if got := forwardedIds(t, output); !reflect.DeepEqual(got, tt.forwarded) {
+			t.Errorf("%v: forwarded %v, want %v", tt.name, got, tt.forwarded)
+		}
+		saved, err := LoadRecordCursors(filepath.Join(dir, "cursors.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := saved.LastId("siem"); got != tt.want {
+			t.Errorf("%v: saved cursor %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// a sink which can't be written to leaves the cursor where it was
+	broken, err := NewRecordSink("file://" + dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.setIds(8, 7)
+	lastId, err := um.forwardNewRecords(broken, "ndjson", cursors, "siem", 7, RecordFilter{})
+	if err != nil || lastId != 7 {
+		t.Errorf("forwardNewRecords(broken sink) = %d, %v, want 7", lastId, err)
+	}
+	if got, _ := cursors.LastId("siem"); got != 7 {
+		t.Errorf("cursor moved to %d by a failed write, want 7", got)
+	}
+}
+
+func TestForwardRecordsSavesStartingPoint(t *testing.T) {
+	dir := t.TempDir()
+	_, um := newRecordServer(t, 3, 2, 1)
+	sink, err := NewRecordSink("file://" + filepath.Join(dir, "records.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the cursors can't be written in a missing directory, which stops the forwarder as soon as it
+	// saves the starting point, before its first poll
+	cursors, err := LoadRecordCursors(filepath.Join(dir, "missing", "cursors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = um.ForwardRecords(sink, "ndjson", cursors, "siem", time.Time{}, time.Hour); err == nil {
+		t.Fatalf("ForwardRecords didn't save its starting point")
+	}
+	if lastId, ok := cursors.LastId("siem"); !ok || lastId != 3 {
+		t.Errorf("starting point = %d, %v, want 3", lastId, ok)
+	}
+}
+
+func TestRecordsAfterSkipsShiftedRecords(t *testing.T) {
+	var ids []int
+	for id := 150; id >= 1; id-- {
+		ids = append(ids, id)
+	}
+	server, um := newRecordServer(t, ids...)
+	// two records are created between the first and the second page, pushing the last two records
+	// of the first page onto the second one
+	server.onPage = func(page int) {
+		if page == 2 {
+			server.setIds(append([]int{152, 151}, ids...)...)
+		}
+	}
+
+	records, err := um.recordsAfter(0, RecordFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[int]bool{}
+	for _, record := range records {
+		if seen[record.Id] {
+			t.Fatalf("record %d collected twice", record.Id)
+		}
+		seen[record.Id] = true
+	}
+	if len(records) != 150 {
+		t.Errorf("recordsAfter() returned %d records, want 150", len(records))
+	}
+}
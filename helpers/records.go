@@ -0,0 +1,223 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordColumns are the columns records are printed with, one record per line.
+var RecordColumns = []string{"Id", "CreatedTime", "Organization", "User", "ClientIp", "Method", "Action", "RequestUri"}
+
+const recordPageSize = 100
+
+// RecordFilter selects audit records. Empty fields and zero times match every record, and Path
+// matches the records whose request URI contains it.
+type RecordFilter struct {
+	User     string
+	Action   string
+	ClientIp string
+	Path     string
+	Since    time.Time
+	Until    time.Time
+}
+
+// ParseRecordTime parses the bound of a time range, either as a duration before now such as 24h, as
+// an RFC 3339 time or as a date. An empty value gives the zero time, which leaves the range open.
+func ParseRecordTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %v (expected a duration such as 24h, an RFC 3339 time or a date such as 2024-01-31)", value)
+}
+
+// SearchRecords returns the latest records of the organization matching the filter, newest first,
+// up to limit records.
+func (um *UserManager) SearchRecords(filter RecordFilter, limit int) ([]map[string]interface{}, error) {
+	var recordList []map[string]interface{}
+	for page := 1; ; page++ {
+		records, total, err := um.getRecordsPage(page, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if created := recordTime(record); !filter.Since.IsZero() && !created.IsZero() && created.Before(filter.Since) {
+				// records come newest first, so the following ones are older as well
+				return recordList, nil
+			}
+			if filter.matches(record) {
+				recordList = append(recordList, recordInfo(record))
+				if len(recordList) >= limit {
+					return recordList, nil
+				}
+			}
+		}
+		if len(records) == 0 || page*recordPageSize >= total {
+			return recordList, nil
+		}
+	}
+}
+
+// TailRecords prints the last lines records matching the filter, and then polls Casdoor every
+// interval for new ones, printing them as they come, until the process is interrupted.
+func (um *UserManager) TailRecords(filter RecordFilter, lines int, interval time.Duration, print func(map[string]interface{})) error {
+	records, _, err := um.getRecordsPage(1, filter)
+	if err != nil {
+		return err
+	}
+	lastId := 0
+	if len(records) > 0 {
+		lastId = records[0].Id
+	}
+	var latest []*casdoorsdk.Record
+	for _, record := range records {
+		if len(latest) < lines && filter.matches(record) {
+			latest = append(latest, record)
+		}
+	}
+	for i := len(latest) - 1; i >= 0; i-- {
+		print(recordInfo(latest[i]))
+	}
+
+	for {
+		time.Sleep(interval)
+		newRecords, err := um.recordsAfter(lastId, filter)
+		if err != nil {
+			utils.Colorize(color.YellowString, "[⚠] failed to poll records: %v", err)
+			continue
+		}
+		// records come newest first, so they are printed backwards
+		for i := len(newRecords) - 1; i >= 0; i-- {
+			if filter.matches(newRecords[i]) {
+				print(recordInfo(newRecords[i]))
+			}
+		}
+		if len(newRecords) > 0 {
+			lastId = newRecords[0].Id
+		}
+	}
+}
+
+// recordsAfter returns the records whose id is greater than lastId, newest first. The records older
+// than the start of the time range of the filter are left out as well. Pages are taken by offset, so
+// the records created during the scan push already collected ones to the next page, where they are
+// skipped.
+func (um *UserManager) recordsAfter(lastId int, filter RecordFilter) ([]*casdoorsdk.Record, error) {
+	var newRecords []*casdoorsdk.Record
+	collected := map[int]bool{}
+	for page := 1; ; page++ {
+		records, _, err := um.getRecordsPage(page, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if record.Id <= lastId {
+				return newRecords, nil
+			}
+			if collected[record.Id] {
+				continue
+			}
+			collected[record.Id] = true
+			if created := recordTime(record); !filter.Since.IsZero() && !created.IsZero() && created.Before(filter.Since) {
+				return newRecords, nil
+			}
+			newRecords = append(newRecords, record)
+		}
+		if len(records) < recordPageSize {
+			return newRecords, nil
+		}
+	}
+}
+
+// getRecordsPage returns a page of the records of the organization, newest first, along with the
+// total number of records. Casdoor filters on a single field, so the user or the action of the
+// filter narrows the search, and the other criteria are checked by RecordFilter.matches. The
+// request is the one of GetPaginationRecords, whose response decoding always fails in the SDK.
+func (um *UserManager) getRecordsPage(page int, filter RecordFilter) ([]*casdoorsdk.Record, int, error) {
+	query := map[string]string{
+		"owner":     um.client.OrganizationName,
+		"p":         strconv.Itoa(page),
+		"pageSize":  strconv.Itoa(recordPageSize),
+		"sortField": "id",
+		"sortOrder": "descend",
+	}
+	if filter.User != "" {
+		query["field"] = "user"
+		query["value"] = filter.User
+	} else if filter.Action != "" {
+		query["field"] = "action"
+		query["value"] = filter.Action
+	}
+
+	response, err := um.client.DoGetResponse(um.client.GetUrl("get-records", query))
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		return nil, 0, err
+	}
+	var records []*casdoorsdk.Record
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, _ := response.Data2.(float64)
+	return records, int(total), nil
+}
+
+func (f RecordFilter) matches(record *casdoorsdk.Record) bool {
+	if f.User != "" && record.User != f.User {
+		return false
+	}
+	if f.Action != "" && record.Action != f.Action {
+		return false
+	}
+	if f.ClientIp != "" && record.ClientIp != f.ClientIp {
+		return false
+	}
+	if f.Path != "" && !strings.Contains(record.RequestUri, f.Path) {
+		return false
+	}
+	created := recordTime(record)
+	if !f.Since.IsZero() && created.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && created.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// recordTime returns the creation time of a record, or the zero time when it can't be parsed.
+func recordTime(record *casdoorsdk.Record) time.Time {
+	created, _ := time.Parse(time.RFC3339, record.CreatedTime)
+	return created
+}
+
+func recordInfo(record *casdoorsdk.Record) map[string]interface{} {
+	return map[string]interface{}{
+		"Id":           record.Id,
+		"CreatedTime":  record.CreatedTime,
+		"Organization": record.Organization,
+		"User":         record.User,
+		"ClientIp":     record.ClientIp,
+		"Method":       record.Method,
+		"Action":       record.Action,
+		"RequestUri":   record.RequestUri,
+	}
+}
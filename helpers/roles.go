@@ -1,12 +1,38 @@
 package helpers
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
 	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"sort"
+	"strings"
+	"time"
 )
 
+// GroupFields holds the editable fields of a group besides its name. An empty Parent
+// makes the group a top group of the organization.
+type GroupFields struct {
+	DisplayName string
+	Type        string
+	Parent      string
+	Manager     string
+}
+
+// GroupTypes are the group types known by Casdoor.
+var GroupTypes = []string{"Virtual", "Physical"}
+
+func checkGroupType(groupType string) error {
+	for _, known := range GroupTypes {
+		if groupType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid type %v (expected one of %v)", groupType, strings.Join(GroupTypes, ", "))
+}
+
 func (um *UserManager) GetGroups() ([]map[string]interface{}, error) {
 	groups, err := um.client.GetGroups()
 
@@ -17,8 +43,12 @@ func (um *UserManager) GetGroups() ([]map[string]interface{}, error) {
 
 	for _, group := range groups {
 		groupInfo := map[string]interface{}{
-			"Name":  group.Name,
-			"Owner": group.Owner,
+			"Name":        group.Name,
+			"Owner":       group.Owner,
+			"DisplayName": group.DisplayName,
+			"Type":        group.Type,
+			"Parent":      um.parentName(group),
+			"Manager":     group.Manager,
 		}
 
 		groupList = append(groupList, groupInfo)
@@ -26,16 +56,27 @@ func (um *UserManager) GetGroups() ([]map[string]interface{}, error) {
 	return groupList, nil
 }
 
-func (um *UserManager) AddGroup() error {
-	name, err := um.promptGroupName()
+func (um *UserManager) AddGroup(fields GroupFields) error {
+	if err := checkGroupType(fields.Type); err != nil {
+		return err
+	}
+	name, err := um.promptGroupName("")
 	if err != nil {
 		return err
 	}
+	if fields.Parent != "" {
+		if _, err = um.findGroup(fields.Parent); err != nil {
+			return err
+		}
+	}
 
 	group := casdoorsdk.Group{
-		Name:  name,
-		Owner: "casdoor-cli",
+		Name:        name,
+		Owner:       um.client.OrganizationName,
+		CreatedTime: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		IsEnabled:   true,
 	}
+	um.applyGroupFields(&group, fields)
 
 	_, err = um.client.AddGroup(&group)
 
@@ -46,70 +87,379 @@ func (um *UserManager) AddGroup() error {
 	return nil
 }
 
-func (um *UserManager) DeleteGroup(name string) error {
-	group := casdoorsdk.Group{
-		Name:  name,
-		Owner: "casdoor-cli",
+// GroupUpdate holds the fields to change on a group. Nil fields are left untouched.
+type GroupUpdate struct {
+	Name        *string
+	DisplayName *string
+	Type        *string
+	Parent      *string
+	Manager     *string
+}
+
+// UpdateGroup updates the group with the given changes, keeping every unspecified field.
+// When no change is given, every field is prompted for, with the current values as defaults.
+// Renaming a group also rewrites the group reference of its members and child groups.
+func (um *UserManager) UpdateGroup(name string, update GroupUpdate) error {
+	newName, members, children, err := um.updateGroup(name, update)
+	if err != nil {
+		return err
+	}
+	if newName != name {
+		utils.Colorize(color.GreenString, "[✔] %v group has been renamed to %v (%d members and %d child groups updated)", name, newName, members, children)
+		return nil
 	}
+	utils.Colorize(color.GreenString, "[✔] %v group has been updated successfully", newName)
+	return nil
+}
 
-	checkGroups, _ := um.client.GetGroups()
-	groupFound := false
+// MoveGroup makes the group a child of parent, or a top group when parent is empty.
+// Moving a group under itself or under one of its descendants is refused.
+func (um *UserManager) MoveGroup(name string, parent string) error {
+	_, _, _, err := um.updateGroup(name, GroupUpdate{Parent: &parent})
+	if err != nil {
+		return err
+	}
+	if parent == "" {
+		utils.Colorize(color.GreenString, "[✔] %v group is now a top group", name)
+	} else {
+		utils.Colorize(color.GreenString, "[✔] %v group has been moved under %v", name, parent)
+	}
+	return nil
+}
 
-	for _, checkGroup := range checkGroups {
-		if checkGroup.Name == name {
-			_, err := um.client.DeleteGroup(&group)
-			if err != nil {
-				return err
-			}
-			utils.Colorize(color.GreenString, "[✔] %v group has been deleted successfully", name)
-			groupFound = true
-			break
+// updateGroup applies the changes of UpdateGroup, and returns the new name of the group along with
+// the number of members and child groups whose reference was renamed.
+func (um *UserManager) updateGroup(name string, update GroupUpdate) (string, int, int, error) {
+	groups, err := um.client.GetGroups()
+	if err != nil {
+		return "", 0, 0, err
+	}
+	groupsByName := map[string]*casdoorsdk.Group{}
+	for _, group := range groups {
+		groupsByName[group.Name] = group
+	}
+
+	checkGroup, ok := groupsByName[name]
+	if !ok {
+		return "", 0, 0, fmt.Errorf("group %v doesn't exist", name)
+	}
+
+	fields := GroupFields{
+		DisplayName: checkGroup.DisplayName,
+		Type:        checkGroup.Type,
+		Parent:      um.parentName(checkGroup),
+		Manager:     checkGroup.Manager,
+	}
+	newName := name
+
+	if update == (GroupUpdate{}) {
+		newName, err = um.promptGroupName(name)
+		if err != nil {
+			return "", 0, 0, err
+		}
+		fields, err = um.promptGroupFields(fields)
+		if err != nil {
+			return "", 0, 0, err
+		}
+	} else {
+		setIfNotNil(&newName, update.Name)
+		setIfNotNil(&fields.DisplayName, update.DisplayName)
+		setIfNotNil(&fields.Type, update.Type)
+		setIfNotNil(&fields.Parent, update.Parent)
+		setIfNotNil(&fields.Manager, update.Manager)
+	}
+
+	if newName != name {
+		if _, exists := groupsByName[newName]; exists {
+			return "", 0, 0, fmt.Errorf("group %v already exists", newName)
+		}
+	}
+	// groups created before the type was validated keep their type unless it is changed
+	if fields.Type != checkGroup.Type {
+		if err = checkGroupType(fields.Type); err != nil {
+			return "", 0, 0, err
 		}
 	}
-	if !groupFound {
-		utils.Colorize(color.RedString, "[x] group %v doesn't exist", name)
+	err = checkGroupParent(groupsByName, name, fields.Parent)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	group := *checkGroup
+	group.Name = newName
+	um.applyGroupFields(&group, fields)
+
+	// the group is addressed by its current id so that a new name renames it
+	err = um.updateGroupById(fmt.Sprintf("%s/%s", checkGroup.Owner, checkGroup.Name), &group)
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	if newName == name {
+		return newName, 0, 0, nil
+	}
+	members, children, err := um.renameGroupReferences(groups, name, newName)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return newName, members, children, nil
+}
+
+// checkGroupParent ensures that parent exists and isn't the group itself or one of its descendants.
+func checkGroupParent(groupsByName map[string]*casdoorsdk.Group, name string, parent string) error {
+	if parent == "" {
+		return nil
+	}
+	if _, ok := groupsByName[parent]; !ok {
+		return fmt.Errorf("group %v doesn't exist", parent)
+	}
+
+	// walk up from the new parent, reaching the group means a cycle
+	visited := map[string]bool{}
+	for ancestor := parent; ancestor != "" && !visited[ancestor]; {
+		if ancestor == name {
+			return fmt.Errorf("moving %v under %v would create a cycle", name, parent)
+		}
+		visited[ancestor] = true
+		ancestorGroup, ok := groupsByName[ancestor]
+		if !ok || ancestorGroup.IsTopGroup {
+			break
+		}
+		ancestor = ancestorGroup.ParentId
 	}
 	return nil
 }
 
-func (um *UserManager) UpdateGroup(name string) error {
-	checkGroups, _ := um.client.GetGroups()
-	groupFound := false
+// renameGroupReferences rewrites the group reference of every member and child group of a renamed
+// group. It returns the number of updated members and child groups.
+func (um *UserManager) renameGroupReferences(groups []*casdoorsdk.Group, name string, newName string) (int, int, error) {
+	users, err := um.client.GetUsers()
+	if err != nil {
+		return 0, 0, err
+	}
 
-	for _, checkGroup := range checkGroups {
-		if checkGroup.Name == name {
-			newName, err := um.promptGroupName()
-			if err != nil {
-				return err
+	members := 0
+	for _, user := range users {
+		userGroups := groupNames(user.Groups)
+		if !containsString(userGroups, name) {
+			continue
+		}
+		for i, group := range userGroups {
+			if group == name {
+				userGroups[i] = newName
 			}
+		}
+		err = um.updateUserGroups(user, userGroups)
+		if err != nil {
+			return members, 0, err
+		}
+		members++
+	}
 
-			group := casdoorsdk.Group{
-				Name:  newName,
-				Owner: checkGroup.Owner,
-			}
+	children := 0
+	for _, group := range groups {
+		if group.IsTopGroup || group.ParentId != name {
+			continue
+		}
+		group.ParentId = newName
+		err = um.updateGroupById(fmt.Sprintf("%s/%s", group.Owner, group.Name), group)
+		if err != nil {
+			return members, children, err
+		}
+		children++
+	}
+	return members, children, nil
+}
+
+// updateGroupById updates the group stored under the given id. Unlike the SDK UpdateGroup,
+// which addresses the group by its new name, this allows renaming a group.
+func (um *UserManager) updateGroupById(id string, group *casdoorsdk.Group) error {
+	postBytes, err := json.Marshal(group)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DoPost("update-group", map[string]string{"id": id}, postBytes, false, false)
+	return err
+}
+
+func setIfNotNil(target *string, value *string) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+// GetGroupTree renders the group hierarchy of the organization along with the number of
+// direct members of each group.
+func (um *UserManager) GetGroupTree() (string, error) {
+	groups, err := um.client.GetGroups()
+	if err != nil {
+		return "", err
+	}
+	users, err := um.client.GetUsers()
+	if err != nil {
+		return "", err
+	}
 
-			_, err = um.client.UpdateGroup(&group)
+	memberCounts := map[string]int{}
+	for _, user := range users {
+		for _, group := range groupNames(user.Groups) {
+			memberCounts[group]++
+		}
+	}
 
-			if err != nil {
-				return err
+	groupsByName := map[string]bool{}
+	for _, group := range groups {
+		groupsByName[group.Name] = true
+	}
+	children := map[string][]*casdoorsdk.Group{}
+	var orphans []*casdoorsdk.Group
+	for _, group := range groups {
+		parent := um.parentName(group)
+		if parent != "" && !groupsByName[parent] {
+			orphans = append(orphans, group)
+			continue
+		}
+		children[parent] = append(children[parent], group)
+	}
+
+	var tree strings.Builder
+	visited := map[string]bool{}
+	var render func(nodes []*casdoorsdk.Group, prefix string)
+	render = func(nodes []*casdoorsdk.Group, prefix string) {
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+		for i, group := range nodes {
+			branch, indent := "├── ", "│   "
+			if i == len(nodes)-1 {
+				branch, indent = "└── ", "    "
 			}
-			utils.Colorize(color.GreenString, "[✔] %v group has been updated successfully", newName)
-			if err != nil {
-				return err
+			tree.WriteString(prefix + branch + group.Name)
+			if group.DisplayName != "" && group.DisplayName != group.Name {
+				tree.WriteString(fmt.Sprintf(" (%s)", group.DisplayName))
+			}
+			if group.Type != "" {
+				tree.WriteString(fmt.Sprintf(" [%s]", group.Type))
+			}
+			tree.WriteString(fmt.Sprintf(" - %d members\n", memberCounts[group.Name]))
+			if !visited[group.Name] {
+				visited[group.Name] = true
+				render(children[group.Name], prefix+indent)
 			}
-			groupFound = true
-			break
 		}
 	}
-	if !groupFound {
-		utils.Colorize(color.RedString, "[x] group %v doesn't exist", name)
+	tree.WriteString(um.client.OrganizationName + "\n")
+	render(children[""], "")
+
+	// groups whose parent is missing, or which sit in a parent cycle, can't be reached from the top
+	// groups, so they are listed on their own rather than hidden
+	if len(orphans) > 0 {
+		var names []string
+		for _, group := range orphans {
+			names = append(names, fmt.Sprintf("%v (parent %v)", group.Name, group.ParentId))
+		}
+		sort.Strings(names)
+		utils.Colorize(color.YellowString, "[⚠] the parent of the groups %v doesn't exist", strings.Join(names, ", "))
+		tree.WriteString("(missing parent)\n")
+		render(orphans, "")
 	}
-	return nil
+	var cyclic []*casdoorsdk.Group
+	for _, group := range groups {
+		if !visited[group.Name] && !containsGroup(orphans, group) {
+			cyclic = append(cyclic, group)
+		}
+	}
+	if len(cyclic) > 0 {
+		var names []string
+		for _, group := range cyclic {
+			names = append(names, group.Name)
+		}
+		sort.Strings(names)
+		utils.Colorize(color.YellowString, "[⚠] the groups %v are in or under a parent cycle", strings.Join(names, ", "))
+		tree.WriteString("(parent cycle)\n")
+		for _, group := range cyclic {
+			visited[group.Name] = true
+		}
+		render(cyclic, "")
+	}
+
+	return tree.String(), nil
+}
+
+func containsGroup(groups []*casdoorsdk.Group, target *casdoorsdk.Group) bool {
+	for _, group := range groups {
+		if group.Name == target.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// applyGroupFields copies the fields onto the group. Casdoor references top groups
+// by using the organization name as parent.
+func (um *UserManager) applyGroupFields(group *casdoorsdk.Group, fields GroupFields) {
+	group.DisplayName = fields.DisplayName
+	if group.DisplayName == "" {
+		group.DisplayName = group.Name
+	}
+	group.Type = fields.Type
+	group.Manager = fields.Manager
+	group.ParentId = fields.Parent
+	group.IsTopGroup = fields.Parent == ""
+	if group.IsTopGroup {
+		group.ParentId = um.client.OrganizationName
+	}
+}
+
+// parentName returns the name of the parent group, or an empty string for a top group.
+func (um *UserManager) parentName(group *casdoorsdk.Group) string {
+	if group.IsTopGroup || group.ParentId == um.client.OrganizationName {
+		return ""
+	}
+	return group.ParentId
+}
+
+func (um *UserManager) promptGroupFields(defaults GroupFields) (GroupFields, error) {
+	var fields GroupFields
+	var err error
+
+	displayNamePrompt := promptui.Prompt{
+		Label:   "Display Name",
+		Default: defaults.DisplayName,
+	}
+	if fields.DisplayName, err = displayNamePrompt.Run(); err != nil {
+		return fields, err
+	}
+
+	typePrompt := promptui.Prompt{
+		Label:   "Type (Virtual or Physical)",
+		Default: defaults.Type,
+	}
+	if fields.Type, err = typePrompt.Run(); err != nil {
+		return fields, err
+	}
+
+	parentPrompt := promptui.Prompt{
+		Label:   "Parent Group (empty for a top group)",
+		Default: defaults.Parent,
+	}
+	if fields.Parent, err = parentPrompt.Run(); err != nil {
+		return fields, err
+	}
+
+	managerPrompt := promptui.Prompt{
+		Label:   "Manager",
+		Default: defaults.Manager,
+	}
+	if fields.Manager, err = managerPrompt.Run(); err != nil {
+		return fields, err
+	}
+
+	return fields, nil
 }
 
-func (um *UserManager) promptGroupName() (string, error) {
+func (um *UserManager) promptGroupName(defaultName string) (string, error) {
 	namePrompt := promptui.Prompt{
-		Label: "Group Name",
+		Label:   "Group Name",
+		Default: defaultName,
 	}
 	return namePrompt.Run()
 }
@@ -0,0 +1,192 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/models"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"os"
+	"strings"
+	"time"
+)
+
+// importedColumns are the user columns written when an imported user already exists.
+var importedColumns = []string{"display_name", "email", "phone", "groups", "is_forbidden", "properties"}
+
+// ExportUsers writes the users of the organization, including their custom properties, to a JSON file.
+func (um *UserManager) ExportUsers(path string) error {
+	users, err := um.client.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	records := []models.UserRecord{}
+	for _, user := range users {
+		records = append(records, models.UserRecord{
+			Name:        user.Name,
+			DisplayName: user.DisplayName,
+			Email:       user.Email,
+			Phone:       user.Phone,
+			Groups:      groupNames(user.Groups),
+			IsForbidden: user.IsForbidden,
+			Properties:  user.Properties,
+		})
+	}
+
+	data, err := json.MarshalIndent(records, "", "    ")
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %d users have been exported to %v", len(records), path)
+	return nil
+}
+
+// importedPasswordLength is the length of the passwords generated for imported users.
+const importedPasswordLength = 16
+
+// ImportUsers reads users from a JSON file written by ExportUsers. Missing users are created,
+// existing ones only get the imported columns updated. Users are created with the password of
+// the file, or with a generated one appended to passwordsPath as soon as the user is created, and
+// nothing is written unless every new user gets a password and every group exists.
+func (um *UserManager) ImportUsers(path string, passwordsPath string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var records []models.UserRecord
+	err = json.Unmarshal(data, &records)
+	if err != nil {
+		return err
+	}
+
+	users := make([]*casdoorsdk.User, len(records))
+	var groups, missingPasswords []string
+	for i, record := range records {
+		users[i], err = um.client.GetUser(record.Name)
+		if err != nil {
+			return err
+		}
+		if users[i] == nil && record.Password == "" {
+			missingPasswords = append(missingPasswords, record.Name)
+		}
+		for _, group := range record.Groups {
+			if !containsString(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	if err = um.checkGroupsExist(groups); err != nil {
+		return err
+	}
+	if len(missingPasswords) > 0 && passwordsPath == "" {
+		return fmt.Errorf("the new users %v have no password, give them one in %v or generate them with --passwords-file", strings.Join(missingPasswords, ", "), path)
+	}
+	options, err := um.GetPasswordOptions()
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if record.Password != "" {
+			if err = CheckPasswordOptions(record.Password, options); err != nil {
+				return fmt.Errorf("invalid password for %v: %v", record.Name, err)
+			}
+		}
+	}
+
+	var passwordsFile *os.File
+	if len(missingPasswords) > 0 {
+		passwordsFile, err = openPasswordsFile(passwordsPath)
+		if err != nil {
+			return err
+		}
+		defer passwordsFile.Close()
+	}
+
+	added, updated, generated := 0, 0, 0
+	for i, record := range records {
+		user := users[i]
+		isNew := user == nil
+		if isNew {
+			password := record.Password
+			if password == "" {
+				password, err = GeneratePassword(options, importedPasswordLength)
+				if err != nil {
+					return importStopped(record.Name, added, updated, err)
+				}
+			}
+			user = &casdoorsdk.User{
+				Name:              record.Name,
+				Owner:             um.client.OrganizationName,
+				Type:              "normal-user",
+				Password:          password,
+				CreatedTime:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+				SignupApplication: um.client.ApplicationName,
+			}
+		}
+		user.DisplayName = record.DisplayName
+		user.Email = record.Email
+		user.Phone = record.Phone
+		user.Groups = um.groupIds(record.Groups)
+		user.IsForbidden = record.IsForbidden
+		user.Properties = record.Properties
+
+		if isNew {
+			_, err = um.client.AddUser(user)
+		} else {
+			_, err = um.client.UpdateUserForColumns(user, importedColumns)
+		}
+		if err != nil {
+			return importStopped(record.Name, added, updated, err)
+		}
+		if isNew {
+			added++
+		} else {
+			updated++
+		}
+
+		if isNew && record.Password == "" {
+			_, err = fmt.Fprintf(passwordsFile, "%s=%s\n", user.Name, user.Password)
+			if err != nil {
+				// the user exists now, so the password is shown rather than lost
+				utils.Colorize(color.RedString, "[x] failed to write the password of %v to %v, it is %v", user.Name, passwordsPath, user.Password)
+				return importStopped(record.Name, added, updated, err)
+			}
+			generated++
+		}
+	}
+
+	if generated > 0 {
+		utils.Colorize(color.GreenString, "[✔] passwords of the %d new users have been written to %v", generated, passwordsPath)
+	}
+	utils.Colorize(color.GreenString, "[✔] %d users have been added and %d updated from %v", added, updated, path)
+	return nil
+}
+
+// importStopped reports an import which failed partway. The passwords generated for the users
+// already created are in the passwords file.
+func importStopped(name string, added int, updated int, cause error) error {
+	return fmt.Errorf("import stopped at user %v, after %d users added and %d updated: %v", name, added, updated, cause)
+}
+
+// openPasswordsFile opens the file the generated passwords are appended to, one name=password line
+// per created user, so that the passwords of a previous import are kept. The file is only readable
+// by its owner.
+func openPasswordsFile(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	// an existing file keeps its permissions on open
+	if err = file.Chmod(0600); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
@@ -1,7 +1,7 @@
 package helpers
 
 import (
-	"errors"
+	"fmt"
 	"github.com/casdoor/casdoor-go-sdk/casdoorsdk"
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
@@ -26,25 +26,48 @@ func NewUserManager(config *models.CasdoorConfig) *UserManager {
 	return &UserManager{client: client}
 }
 
-func (um *UserManager) GetUsers() ([]map[string]interface{}, error) {
+// GetUsers returns the users of the organization holding every one of the given properties.
+func (um *UserManager) GetUsers(properties map[string]string) ([]map[string]interface{}, error) {
 	users, err := um.client.GetUsers()
 
 	if err != nil {
 		return nil, err
 	}
+	return userList(users, false, properties), nil
+}
+
+// GetGlobalUsers returns the users of every organization holding every one of the given properties,
+// along with the organization they belong to.
+func (um *UserManager) GetGlobalUsers(properties map[string]string) ([]map[string]interface{}, error) {
+	users, err := um.client.GetGlobalUsers()
+
+	if err != nil {
+		return nil, err
+	}
+	return userList(users, true, properties), nil
+}
+
+func userList(users []*casdoorsdk.User, withOwner bool, properties map[string]string) []map[string]interface{} {
 	var userList []map[string]interface{}
 
 	for _, user := range users {
+		if !matchProperties(user.Properties, properties) {
+			continue
+		}
 		userInfo := map[string]interface{}{
-			"Name":  user.Name,
-			"Email": user.Email,
-			"Id":    user.Id,
-			"Group": strings.TrimPrefix(strings.TrimPrefix(user.Groups[0], "casdoor-cli/"), "casdoor-cli/"),
+			"Name":     user.Name,
+			"Email":    user.Email,
+			"Id":       user.Id,
+			"Groups":   strings.Join(groupNames(user.Groups), ", "),
+			"Disabled": user.IsForbidden,
+		}
+		if withOwner {
+			userInfo["Organization"] = user.Owner
 		}
 
 		userList = append(userList, userInfo)
 	}
-	return userList, nil
+	return userList
 }
 
 func (um *UserManager) AddUser() error {
@@ -53,20 +76,20 @@ func (um *UserManager) AddUser() error {
 		return err
 	}
 
-	rolesResult, err := um.promptUserRoles()
+	groups, err := um.promptUserGroups(nil)
 	if err != nil {
 		return err
 	}
 
 	user := casdoorsdk.User{
 		Name:              name,
-		Owner:             "casdoor-cli",
+		Owner:             um.client.OrganizationName,
 		Email:             email,
 		Password:          password,
-		Groups:            []string{rolesResult},
+		Groups:            um.groupIds(groups),
 		Type:              "normal-user",
 		CreatedTime:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-		SignupApplication: "casdoor-cli",
+		SignupApplication: um.client.ApplicationName,
 	}
 
 	_, err = um.client.AddUser(&user)
@@ -81,7 +104,7 @@ func (um *UserManager) AddUser() error {
 func (um *UserManager) DeleteUser(name string) error {
 	user := casdoorsdk.User{
 		Name:  name,
-		Owner: "casdoor-cli",
+		Owner: um.client.OrganizationName,
 	}
 
 	checkUsers, _ := um.client.GetUsers()
@@ -120,12 +143,12 @@ func (um *UserManager) UpdateUser(name string) error {
 				return err
 			}
 
-			password, err := um.promptUserPassword()
+			password, err := um.PromptUserPassword()
 			if err != nil {
 				return err
 			}
 
-			rolesResult, err := um.promptUserRoles()
+			groups, err := um.promptUserGroups(groupNames(userInfo.Groups))
 			if err != nil {
 				return err
 			}
@@ -136,7 +159,7 @@ func (um *UserManager) UpdateUser(name string) error {
 				Owner:             userInfo.Owner,
 				Email:             email,
 				Password:          password,
-				Groups:            []string{rolesResult},
+				Groups:            um.groupIds(groups),
 				Type:              "normal-user",
 				CreatedTime:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 				SignupApplication: userInfo.SignupApplication,
@@ -162,6 +185,28 @@ func (um *UserManager) UpdateUser(name string) error {
 	return nil
 }
 
+// SetUserForbidden forbids or allows the sign in of the user. Only the is_forbidden column
+// is written so that every other field of the user stays untouched.
+func (um *UserManager) SetUserForbidden(name string, forbidden bool) error {
+	user, err := um.findUser(name)
+	if err != nil {
+		return err
+	}
+
+	user.IsForbidden = forbidden
+	_, err = um.client.UpdateUserForColumns(user, []string{"is_forbidden"})
+	if err != nil {
+		return err
+	}
+
+	if forbidden {
+		utils.Colorize(color.GreenString, "[✔] %v has been disabled successfully", name)
+	} else {
+		utils.Colorize(color.GreenString, "[✔] %v has been enabled successfully", name)
+	}
+	return nil
+}
+
 func (um *UserManager) promptUserInput() (string, string, string, error) {
 	namePrompt := promptui.Prompt{
 		Label: "Name",
@@ -179,7 +224,7 @@ func (um *UserManager) promptUserInput() (string, string, string, error) {
 		return "", "", "", err
 	}
 
-	password, err := um.promptUserPassword()
+	password, err := um.PromptUserPassword()
 	if err != nil {
 		return "", "", "", err
 	}
@@ -187,21 +232,34 @@ func (um *UserManager) promptUserInput() (string, string, string, error) {
 	return name, email, password, nil
 }
 
-func (um *UserManager) promptUserRoles() (string, error) {
-	var rolesName []string
-	roles, _ := um.client.GetGroups()
+func (um *UserManager) promptUserGroups(defaultGroups []string) ([]string, error) {
+	var groupsName []string
+	groups, _ := um.client.GetGroups()
 
-	for _, role := range roles {
-		rolesName = append(rolesName, role.Name)
+	for _, group := range groups {
+		groupsName = append(groupsName, group.Name)
+	}
+
+	validate := func(input string) error {
+		for _, name := range splitList(input) {
+			if !containsString(groupsName, name) {
+				return fmt.Errorf("unknown group %v (available: %v)", name, strings.Join(groupsName, ", "))
+			}
+		}
+		return nil
 	}
 
-	rolesPrompt := promptui.Select{
-		Label: "Roles",
-		Items: rolesName,
+	groupsPrompt := promptui.Prompt{
+		Label:    "Groups (comma separated)",
+		Default:  strings.Join(defaultGroups, ", "),
+		Validate: validate,
+	}
+	groupsResult, err := groupsPrompt.Run()
+	if err != nil {
+		return nil, err
 	}
-	_, rolesResult, err := rolesPrompt.Run()
 
-	return rolesResult, err
+	return splitList(groupsResult), nil
 }
 
 func (um *UserManager) promptUserEmail(defaultEmail string) (string, error) {
@@ -214,12 +272,14 @@ func (um *UserManager) promptUserEmail(defaultEmail string) (string, error) {
 	return email, err
 }
 
-func (um *UserManager) promptUserPassword() (string, error) {
+// PromptUserPassword prompts for a password satisfying the organization password options.
+func (um *UserManager) PromptUserPassword() (string, error) {
+	options, err := um.GetPasswordOptions()
+	if err != nil {
+		options = defaultPasswordOptions
+	}
 	validate := func(input string) error {
-		if len(input) < 6 {
-			return errors.New("password must have more than 6 characters")
-		}
-		return nil
+		return CheckPasswordOptions(input, options)
 	}
 	passwordPrompt := promptui.Prompt{
 		Label:    "Password",
@@ -0,0 +1,143 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// webhookMaxBodySize is the largest event the listener accepts, far above the size of a record.
+const webhookMaxBodySize = 1 << 20
+
+// webhookScriptTimeout is how long the script may run for one event before it is killed.
+const webhookScriptTimeout = time.Minute
+
+// webhookMaxPendingScripts is how many events may wait for the script of a previous event. Further
+// events are refused rather than piling up.
+const webhookMaxPendingScripts = 4
+
+// WebhookListener receives the events Casdoor delivers to webhooks and prints them. When Headers
+// is set, events missing one of these headers are rejected, and when Script is set, it is run for
+// each accepted event.
+type WebhookListener struct {
+	Address string
+	Port    int
+	Headers map[string]string
+	Script  string
+
+	// scripts serializes the script runs, and pending counts the events running or waiting for it
+	scripts sync.Mutex
+	pending atomic.Int32
+}
+
+// Listen serves the listener on the address of the listener until the process is interrupted.
+// Running a script for events anyone can send would let them run it at will, so a script requires
+// headers to check.
+func (l *WebhookListener) Listen() error {
+	if l.Script != "" && len(l.Headers) == 0 {
+		return fmt.Errorf("running a script for each event requires a header the events must carry")
+	}
+	address := net.JoinHostPort(l.Address, strconv.Itoa(l.Port))
+	server := &http.Server{
+		Addr:              address,
+		Handler:           l,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		// the script runs before the response is written, possibly after the scripts of the
+		// pending events
+		WriteTimeout: (webhookMaxPendingScripts+1)*webhookScriptTimeout + 30*time.Second,
+		IdleTimeout:  time.Minute,
+	}
+	utils.Colorize(color.CyanString, "[ℹ] listening for Casdoor events on %v, press Ctrl+C to stop", address)
+	return server.ListenAndServe()
+}
+
+// ServeHTTP prints an event, along with the action and the user it is about when it is a Casdoor
+// record, and passes it on to the script.
+func (l *WebhookListener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, webhookMaxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	received := time.Now().Format("15:04:05")
+	for key, value := range l.Headers {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(key)), []byte(value)) != 1 {
+			utils.Colorize(color.RedString, "[x] %v %v %v rejected: header %v doesn't match", received, r.Method, r.URL.Path, key)
+			http.Error(w, "invalid headers", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var event map[string]interface{}
+	if json.Unmarshal(body, &event) == nil && event["action"] != nil {
+		utils.Colorize(color.GreenString, "[✔] %v %v by %v/%v from %v", received, event["action"], event["organization"], event["user"], event["clientIp"])
+	} else {
+		utils.Colorize(color.GreenString, "[✔] %v %v %v", received, r.Method, r.URL.Path)
+	}
+	var pretty bytes.Buffer
+	if json.Indent(&pretty, body, "", "  ") == nil {
+		fmt.Println(pretty.String())
+	} else {
+		fmt.Println(string(body))
+	}
+
+	if l.Script != "" {
+		if l.pending.Add(1) > webhookMaxPendingScripts+1 {
+			l.pending.Add(-1)
+			utils.Colorize(color.YellowString, "[⚠] %v not run, too many events are waiting for it", l.Script)
+			http.Error(w, "too many pending events", http.StatusServiceUnavailable)
+			return
+		}
+		err = l.runScript(body, event)
+		l.pending.Add(-1)
+		if err != nil {
+			utils.Colorize(color.YellowString, "[⚠] %v failed: %v", l.Script, err)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// runScript runs the script with the event on its standard input, and its action and user in the
+// CASDOOR_EVENT_ACTION and CASDOOR_EVENT_USER environment variables. Scripts run one at a time, and
+// are killed after webhookScriptTimeout.
+func (l *WebhookListener) runScript(body []byte, event map[string]interface{}) error {
+	l.scripts.Lock()
+	defer l.scripts.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookScriptTimeout)
+	defer cancel()
+	script := exec.CommandContext(ctx, l.Script)
+	script.Stdin = bytes.NewReader(body)
+	script.Stdout = os.Stdout
+	script.Stderr = os.Stderr
+	script.Env = os.Environ()
+	if event != nil {
+		script.Env = append(script.Env,
+			fmt.Sprintf("CASDOOR_EVENT_ACTION=%v", valueOrEmpty(event["action"])),
+			fmt.Sprintf("CASDOOR_EVENT_USER=%v", valueOrEmpty(event["user"])),
+		)
+	}
+	return script.Run()
+}
+
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,272 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"gitlab.com/sdv9972401/casdoor-cli/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Values accepted by Casdoor for the webhook settings.
+var (
+	WebhookMethods      = []string{"POST", "GET", "PUT", "DELETE"}
+	WebhookContentTypes = []string{"application/json", "application/x-www-form-urlencoded"}
+)
+
+// WebhookFields holds the fields of a new webhook besides its name. Events are Casdoor actions,
+// such as signup, login or delete-user.
+type WebhookFields struct {
+	Url            string
+	Method         string
+	ContentType    string
+	Headers        map[string]string
+	Events         []string
+	IsUserExtended bool
+	IsEnabled      bool
+}
+
+// WebhookUpdate holds the fields to change on a webhook. Nil fields are preserved. Headers are
+// merged into the existing ones, and a header with an empty value is removed.
+type WebhookUpdate struct {
+	Url            *string
+	Method         *string
+	ContentType    *string
+	Headers        map[string]string
+	Events         *[]string
+	IsUserExtended *bool
+	IsEnabled      *bool
+}
+
+// GetWebhooks returns the webhooks of the organization. The Webhook struct of the SDK has the fields
+// of a syncer rather than the url, headers and events of a webhook, so webhooks are handled as raw
+// JSON. Casdoor keeps them under admin, along with the organization they belong to, as its web UI does.
+func (um *UserManager) GetWebhooks() ([]map[string]interface{}, error) {
+	url := um.client.GetUrl("get-webhooks", map[string]string{"owner": "admin", "organization": um.client.OrganizationName})
+	data, err := um.client.DoGetBytes(url)
+	if err != nil {
+		return nil, err
+	}
+	var webhooks []map[string]interface{}
+	err = json.Unmarshal(data, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+	var webhookList []map[string]interface{}
+
+	for _, webhook := range webhooks {
+		webhookList = append(webhookList, map[string]interface{}{
+			"Name":      webhook["name"],
+			"Url":       webhook["url"],
+			"Method":    webhook["method"],
+			"Events":    strings.Join(rawStrings(webhook, "events"), ", "),
+			"IsEnabled": webhook["isEnabled"],
+		})
+	}
+	return webhookList, nil
+}
+
+// GetWebhook returns the settings of a webhook.
+func (um *UserManager) GetWebhook(name string) (map[string]interface{}, error) {
+	webhook, err := um.findWebhook(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var headers []string
+	for key, value := range webhookHeaders(webhook) {
+		headers = append(headers, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(headers)
+	return map[string]interface{}{
+		"Name":           webhook["name"],
+		"Organization":   webhook["organization"],
+		"CreatedTime":    webhook["createdTime"],
+		"Url":            webhook["url"],
+		"Method":         webhook["method"],
+		"ContentType":    webhook["contentType"],
+		"Headers":        strings.Join(headers, ", "),
+		"Events":         strings.Join(rawStrings(webhook, "events"), ", "),
+		"IsUserExtended": webhook["isUserExtended"],
+		"IsEnabled":      webhook["isEnabled"],
+	}, nil
+}
+
+// AddWebhook creates a webhook for the organization.
+func (um *UserManager) AddWebhook(name string, fields WebhookFields) error {
+	if _, err := um.findWebhook(name); err == nil {
+		return fmt.Errorf("webhook %v already exists", name)
+	}
+	if fields.Url == "" {
+		return fmt.Errorf("the url of the webhook is required")
+	}
+	if err := checkWebhookSettings(fields.Method, fields.ContentType); err != nil {
+		return err
+	}
+
+	webhook := map[string]interface{}{
+		"owner":          "admin",
+		"name":           name,
+		"createdTime":    time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		"organization":   um.client.OrganizationName,
+		"url":            fields.Url,
+		"method":         fields.Method,
+		"contentType":    fields.ContentType,
+		"headers":        rawWebhookHeaders(fields.Headers),
+		"events":         nonNilList(fields.Events),
+		"isUserExtended": fields.IsUserExtended,
+		"isEnabled":      fields.IsEnabled,
+	}
+	postBytes, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+	_, err = um.client.DoPost("add-webhook", nil, postBytes, false, false)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v webhook has been created successfully", name)
+	return nil
+}
+
+// UpdateWebhook changes the given settings of a webhook and preserves the other ones.
+func (um *UserManager) UpdateWebhook(name string, update WebhookUpdate) error {
+	webhook, err := um.findWebhook(name)
+	if err != nil {
+		return err
+	}
+
+	method, _ := webhook["method"].(string)
+	if update.Method != nil {
+		method = *update.Method
+	}
+	contentType, _ := webhook["contentType"].(string)
+	if update.ContentType != nil {
+		contentType = *update.ContentType
+	}
+	if err = checkWebhookSettings(method, contentType); err != nil {
+		return err
+	}
+
+	changed := false
+	setRaw := func(key string, value interface{}) {
+		webhook[key] = value
+		changed = true
+	}
+	if update.Url != nil {
+		setRaw("url", *update.Url)
+	}
+	if update.Method != nil {
+		setRaw("method", method)
+	}
+	if update.ContentType != nil {
+		setRaw("contentType", contentType)
+	}
+	if len(update.Headers) > 0 {
+		headers := webhookHeaders(webhook)
+		for key, value := range update.Headers {
+			if value == "" {
+				delete(headers, key)
+			} else {
+				headers[key] = value
+			}
+		}
+		setRaw("headers", rawWebhookHeaders(headers))
+	}
+	if update.Events != nil {
+		setRaw("events", nonNilList(*update.Events))
+	}
+	if update.IsUserExtended != nil {
+		setRaw("isUserExtended", *update.IsUserExtended)
+	}
+	if update.IsEnabled != nil {
+		setRaw("isEnabled", *update.IsEnabled)
+	}
+	if !changed {
+		return fmt.Errorf("nothing to update on webhook %v", name)
+	}
+
+	err = um.updateRawObject("update-webhook", fmt.Sprintf("%v/%v", webhook["owner"], name), webhook)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v webhook has been updated successfully", name)
+	return nil
+}
+
+// DeleteWebhook deletes a webhook.
+func (um *UserManager) DeleteWebhook(name string) error {
+	webhook, err := um.findWebhook(name)
+	if err != nil {
+		return err
+	}
+
+	err = um.updateRawObject("delete-webhook", fmt.Sprintf("%v/%v", webhook["owner"], name), webhook)
+	if err != nil {
+		return err
+	}
+	utils.Colorize(color.GreenString, "[✔] %v webhook has been deleted successfully", name)
+	return nil
+}
+
+// findWebhook looks for a webhook among the ones of admin first, and then among the ones owned by
+// the organization, such as the webhooks created through the SDK.
+func (um *UserManager) findWebhook(name string) (map[string]interface{}, error) {
+	for _, owner := range []string{"admin", um.client.OrganizationName} {
+		url := um.client.GetUrl("get-webhook", map[string]string{"id": fmt.Sprintf("%s/%s", owner, name)})
+		data, err := um.client.DoGetBytes(url)
+		if err != nil {
+			return nil, err
+		}
+		var webhook map[string]interface{}
+		err = json.Unmarshal(data, &webhook)
+		if err != nil {
+			return nil, err
+		}
+		if webhook != nil {
+			return webhook, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook %v doesn't exist", name)
+}
+
+// webhookHeaders returns the headers of a raw webhook, which Casdoor stores as a list of name and value pairs.
+func webhookHeaders(webhook map[string]interface{}) map[string]string {
+	headers := map[string]string{}
+	items, _ := webhook["headers"].([]interface{})
+	for _, item := range items {
+		header, _ := item.(map[string]interface{})
+		key, _ := header["name"].(string)
+		value, _ := header["value"].(string)
+		if key != "" {
+			headers[key] = value
+		}
+	}
+	return headers
+}
+
+func rawWebhookHeaders(headers map[string]string) []map[string]string {
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := []map[string]string{}
+	for _, key := range keys {
+		items = append(items, map[string]string{"name": key, "value": headers[key]})
+	}
+	return items
+}
+
+func checkWebhookSettings(method string, contentType string) error {
+	if !containsString(WebhookMethods, method) {
+		return fmt.Errorf("invalid method %v (expected one of %v)", method, strings.Join(WebhookMethods, ", "))
+	}
+	if !containsString(WebhookContentTypes, contentType) {
+		return fmt.Errorf("invalid content type %v (expected one of %v)", contentType, strings.Join(WebhookContentTypes, ", "))
+	}
+	return nil
+}
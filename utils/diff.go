@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"strings"
+)
+
+// DiffLines compares two texts line by line. Every line of the result is prefixed with "-" when
+// it only exists in the old text, "+" when it only exists in the new one and " " otherwise.
+func DiffLines(oldText string, newText string) []string {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	// lengths[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	lengths := make([][]int, len(oldLines)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			diff = append(diff, " "+oldLines[i])
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			diff = append(diff, "-"+oldLines[i])
+			i++
+		default:
+			diff = append(diff, "+"+newLines[j])
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		diff = append(diff, "-"+oldLines[i])
+	}
+	for ; j < len(newLines); j++ {
+		diff = append(diff, "+"+newLines[j])
+	}
+	return diff
+}
+
+// HasChanges returns true if the diff contains added or removed lines.
+func HasChanges(diff []string) bool {
+	for _, line := range diff {
+		if !strings.HasPrefix(line, " ") {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintDiff prints a diff returned by DiffLines, removed lines in red and added lines in green.
+func PrintDiff(diff []string) {
+	for _, line := range diff {
+		switch {
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(color.RedString("%s", line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(color.GreenString("%s", line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
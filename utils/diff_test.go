@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		oldText string
+		newText string
+		want    []string
+	}{
+		{"identical", "a\nb\n", "a\nb", []string{" a", " b"}},
+		{"both empty", "", "", nil},
+		{"added to empty", "", "a\nb", []string{"+a", "+b"}},
+		{"removed all", "a\nb", "", []string{"-a", "-b"}},
+		{"changed line", "a\nb\nc", "a\nx\nc", []string{" a", "-b", "+x", " c"}},
+		{"appended line", "a\nb", "a\nb\nc", []string{" a", " b", "+c"}},
+		{"removed first line", "a\nb\nc", "b\nc", []string{"-a", " b", " c"}},
+		{"windows line endings", "a\r\nb\r\n", "a\nb\n", []string{" a", " b"}},
+	}
+	for _, test := range tests {
+		got := DiffLines(test.oldText, test.newText)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: DiffLines(%q, %q) = %q, want %q", test.name, test.oldText, test.newText, got, test.want)
+		}
+	}
+}
+
+func TestHasChanges(t *testing.T) {
+	tests := []struct {
+		diff []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{" a", " b"}, false},
+		{[]string{" a", "+b"}, true},
+		{[]string{"-a"}, true},
+	}
+	for _, test := range tests {
+		if got := HasChanges(test.diff); got != test.want {
+			t.Errorf("HasChanges(%q) = %v, want %v", test.diff, got, test.want)
+		}
+	}
+}
@@ -16,8 +16,9 @@ const chunkSize int = 1024
 func TokenDataToKeyring(tokenData *models.TokenData) error {
 	var roleNames []string
 
+	// groups are given as "<organization>/<group>", only the group name is kept
 	for _, role := range tokenData.IDTokenClaims.Groups {
-		roleNames = append(roleNames, role)
+		roleNames = append(roleNames, role[strings.LastIndex(role, "/")+1:])
 	}
 
 	if len(roleNames) == 0 {
@@ -34,7 +35,7 @@ func TokenDataToKeyring(tokenData *models.TokenData) error {
 		"id":            tokenData.IDTokenClaims.Sub,
 		"jti":           strings.TrimPrefix(tokenData.IDTokenClaims.Jti, "admin/"),
 		"is_admin":      strconv.FormatBool(tokenData.IDTokenClaims.IsAdmin),
-		"groups":        strings.TrimPrefix(strings.Join(tokenData.IDTokenClaims.Groups, ", "), "casdoor-cli/"),
+		"groups":        strings.Join(roleNames, ", "),
 	}
 	for key, value := range keyringData {
 		err := saveChunkedData("casdoor-cli", key, value)
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+// OutputFormats are the formats of the --output flag.
+var OutputFormats = []string{"table", "json", "yaml"}
+
+// OutputFormat is the format PrintTable, PrintTables and PrintLine write in, set by the --output flag.
+var OutputFormat = "table"
+
+// CheckOutputFormat returns an error when OutputFormat isn't one of OutputFormats.
+func CheckOutputFormat() error {
+	for _, format := range OutputFormats {
+		if OutputFormat == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %v (expected one of %v)", OutputFormat, strings.Join(OutputFormats, ", "))
+}
+
+// PrintLine writes an item on its own, so that items can be streamed as they come: a line holding
+// the values of the given columns in table format, a single line of JSON in json format, and a YAML
+// document in yaml format.
+func PrintLine(item map[string]interface{}, columns []string) {
+	switch OutputFormat {
+	case "json":
+		data, err := json.Marshal(item)
+		if err != nil {
+			fmt.Printf("%v\n", item)
+			return
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		fmt.Print("---\n" + toYaml(item))
+	default:
+		var values []string
+		for _, column := range columns {
+			values = append(values, fmt.Sprintf("%v", item[column]))
+		}
+		fmt.Println(strings.Join(values, "  "))
+	}
+}
+
+// printStructured writes a value as indented JSON or as YAML, depending on OutputFormat.
+func printStructured(value interface{}) {
+	if OutputFormat == "yaml" {
+		fmt.Print(toYaml(value))
+		return
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		fmt.Printf("%v\n", value)
+		return
+	}
+	fmt.Println(string(data))
+}
+
+func toYaml(value interface{}) string {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v\n", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/fatih/color"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ShowOnce prints a secret on the terminal, waits for the user to press enter and then erases it,
+// so that it doesn't stay in the terminal scrollback.
+func ShowOnce(label string, secret string) {
+	fmt.Printf("%s: %s\n", label, secret)
+	fmt.Print(color.YellowString("[⚠] this secret won't be shown again. Press enter once you have saved it..."))
+	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	// move the cursor up and clear both lines
+	fmt.Print("\033[1A\033[2K\033[1A\033[2K\r")
+}
+
+// MaskSecret hides a secret, only keeping its last 4 characters when it is long enough to stay secret.
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 16 {
+		return "********"
+	}
+	return "********" + secret[len(secret)-4:]
+}
+
+// StagedFile is the new content of a file, written next to it, which only replaces the file once
+// committed. Until then, the file keeps its previous content.
+type StagedFile struct {
+	path string
+	temp string
+}
+
+// StageSecretFile stages a secret alone in a file only readable by its owner.
+func StageSecretFile(path string, secret string) (*StagedFile, error) {
+	return stageFile(path, []byte(secret+"\n"))
+}
+
+// StageEnvFile stages a variable of an env file, such as a .env file read by docker compose, keeping
+// its other lines. The file is created on commit if it doesn't exist.
+func StageEnvFile(path string, key string, value string) (*StagedFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var lines []string
+	found := false
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		name, _, ok := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		if ok && strings.TrimSpace(name) == key {
+			lines[i] = fmt.Sprintf("%s=%s", key, value)
+			if strings.HasPrefix(trimmed, "export ") {
+				lines[i] = "export " + lines[i]
+			}
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, fmt.Sprintf("%s=%s", key, value))
+	}
+	return stageFile(path, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+// Commit replaces the file with its staged content.
+func (f *StagedFile) Commit() error {
+	return os.Rename(f.temp, f.path)
+}
+
+// Discard drops the staged content, leaving the file as it was.
+func (f *StagedFile) Discard() {
+	_ = os.Remove(f.temp)
+}
+
+// stageFile writes data to a temporary file of the directory of path, so that it can be renamed to
+// path atomically.
+func stageFile(path string, data []byte) (*StagedFile, error) {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, err
+	}
+	// CreateTemp already restricts the file to its owner
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(temp.Name())
+		return nil, err
+	}
+	return &StagedFile{path: path, temp: temp.Name()}, nil
+}
@@ -7,6 +7,10 @@ import (
 )
 
 func PrintTable(inputMap map[string]interface{}) {
+	if OutputFormat != "table" {
+		printStructured(inputMap)
+		return
+	}
 	table := tablewriter.NewWriter(os.Stdout)
 
 	var headers []string
@@ -37,6 +41,13 @@ func PrintTable(inputMap map[string]interface{}) {
 }
 
 func PrintTables(items []map[string]interface{}) {
+	if OutputFormat != "table" {
+		if items == nil {
+			items = []map[string]interface{}{}
+		}
+		printStructured(items)
+		return
+	}
 	table := tablewriter.NewWriter(os.Stdout)
 
 	var headers []string